@@ -48,6 +48,7 @@ type TestingT interface {
 // Checker provides some simple helpers for testing.
 type Checker struct {
 	TestingT
+	diffOptions []DiffOption
 }
 
 // New creates a new Checker. Typically used by calling check.New(t), where t is a *testing.T.
@@ -55,11 +56,16 @@ func New(t TestingT) Checker {
 	return Checker{TestingT: t}
 }
 
-// Equal compares two values for equality.
+// Equal compares two values for equality. When the values differ, the failure message includes the paths to each
+// difference found within them, as reported by Diff().
 func (c Checker) Equal(expected, actual any, msgAndArgs ...any) {
 	c.Helper()
 	if !c.equal(expected, actual) {
-		c.errMsg(fmt.Sprintf("Expected %v, got %v", expected, actual), msgAndArgs...)
+		msg := fmt.Sprintf("Expected %v, got %v", expected, actual)
+		if diffs := Diff(expected, actual, c.diffOptions...); len(diffs) != 0 && !isSimpleDiff(expected) {
+			msg += "\nDifferences (expected != actual):\n  " + strings.Join(diffs, "\n  ")
+		}
+		c.errMsg(msg, msgAndArgs...)
 	}
 }
 
@@ -72,6 +78,9 @@ func (c Checker) NotEqual(expected, actual any, msgAndArgs ...any) {
 }
 
 func (c Checker) equal(expected, actual any) bool {
+	if len(c.diffOptions) != 0 {
+		return len(Diff(expected, actual, c.diffOptions...)) == 0
+	}
 	if expected == nil || actual == nil {
 		return expected == actual
 	}
@@ -89,6 +98,22 @@ func (c Checker) equal(expected, actual any) bool {
 	return bytes.Equal(exp, act)
 }
 
+// isSimpleDiff returns true if the value is simple enough that the "Expected %v, got %v" message is already as clear as
+// a diff would be.
+func isSimpleDiff(value any) bool {
+	if s, ok := value.(string); ok {
+		return !strings.Contains(s, "\n")
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Invalid, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32,
+		reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
 // Nil expects value to be nil.
 func (c Checker) Nil(value any, msgAndArgs ...any) {
 	c.Helper()
@@ -0,0 +1,163 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Len expects value, which must be a string, slice, array, map, or channel, to have the specified length.
+func (c Checker) Len(value any, length int, msgAndArgs ...any) {
+	c.Helper()
+	n, ok := lengthOf(value)
+	switch {
+	case !ok:
+		c.errMsg(fmt.Sprintf("Unable to determine the length of %T", value), msgAndArgs...)
+	case n != length:
+		c.errMsg(fmt.Sprintf("Expected length %d, got %d for %v", length, n, value), msgAndArgs...)
+	}
+}
+
+// Empty expects value to be empty, which means it is nil, has a length of zero, or is the zero value for its type.
+func (c Checker) Empty(value any, msgAndArgs ...any) {
+	c.Helper()
+	if !isEmpty(value) {
+		c.errMsg(fmt.Sprintf("Expected empty, instead got %v", value), msgAndArgs...)
+	}
+}
+
+// NotEmpty expects value to not be empty. See Empty() for what is considered empty.
+func (c Checker) NotEmpty(value any, msgAndArgs ...any) {
+	c.Helper()
+	if isEmpty(value) {
+		c.errMsg(fmt.Sprintf("Expected a non-empty value, instead got %v", value), msgAndArgs...)
+	}
+}
+
+// ElementsMatch expects the slices or arrays expected and actual to contain the same elements, including the same
+// number of any duplicates, without regard to order.
+func (c Checker) ElementsMatch(expected, actual any, msgAndArgs ...any) {
+	c.Helper()
+	exp, ok := c.listValue(expected, msgAndArgs...)
+	if !ok {
+		return
+	}
+	act, ok2 := c.listValue(actual, msgAndArgs...)
+	if !ok2 {
+		return
+	}
+	missing, extra := c.unmatched(exp, act)
+	if len(missing) != 0 || len(extra) != 0 {
+		var buffer strings.Builder
+		fmt.Fprintf(&buffer, "Expected elements %v to match %v", actual, expected)
+		if len(missing) != 0 {
+			fmt.Fprintf(&buffer, "; missing %v", missing)
+		}
+		if len(extra) != 0 {
+			fmt.Fprintf(&buffer, "; extra %v", extra)
+		}
+		c.errMsg(buffer.String(), msgAndArgs...)
+	}
+}
+
+// Subset expects every element of subset to be present in list. Both may be slices or arrays, in which case duplicate
+// elements in subset must be matched by an equal number in list, or maps, in which case every key in subset must be
+// present in list with an equal value.
+func (c Checker) Subset(list, subset any, msgAndArgs ...any) {
+	c.Helper()
+	lv := reflect.ValueOf(list)
+	sv := reflect.ValueOf(subset)
+	if lv.Kind() == reflect.Map && sv.Kind() == reflect.Map {
+		for _, k := range sv.MapKeys() {
+			v := lv.MapIndex(k)
+			if !v.IsValid() || !c.equal(v.Interface(), sv.MapIndex(k).Interface()) {
+				c.errMsg(fmt.Sprintf("Expected %v to contain %v: %v", list, formatValue(k), sv.MapIndex(k)),
+					msgAndArgs...)
+				return
+			}
+		}
+		return
+	}
+	l, ok := c.listValue(list, msgAndArgs...)
+	if !ok {
+		return
+	}
+	s, ok2 := c.listValue(subset, msgAndArgs...)
+	if !ok2 {
+		return
+	}
+	if missing, _ := c.unmatched(s, l); len(missing) != 0 {
+		c.errMsg(fmt.Sprintf("Expected %v to contain %v, but it is missing %v", list, subset, missing), msgAndArgs...)
+	}
+}
+
+func (c Checker) listValue(value any, msgAndArgs ...any) ([]any, bool) {
+	c.Helper()
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		list := make([]any, v.Len())
+		for i := range list {
+			list[i] = v.Index(i).Interface()
+		}
+		return list, true
+	default:
+		c.errMsg(fmt.Sprintf("Expected a slice or array, got %T", value), msgAndArgs...)
+		return nil, false
+	}
+}
+
+// unmatched returns the elements of expected that have no counterpart in actual, and the elements of actual that have
+// no counterpart in expected.
+func (c Checker) unmatched(expected, actual []any) (missing, extra []any) {
+	used := make([]bool, len(actual))
+outer:
+	for _, e := range expected {
+		for i, a := range actual {
+			if !used[i] && c.equal(e, a) {
+				used[i] = true
+				continue outer
+			}
+		}
+		missing = append(missing, e)
+	}
+	for i, a := range actual {
+		if !used[i] {
+			extra = append(extra, a)
+		}
+	}
+	return missing, extra
+}
+
+func lengthOf(value any) (int, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return v.Len(), true
+	case reflect.Pointer:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Array {
+			return v.Elem().Len(), true
+		}
+	default:
+	}
+	return 0, false
+}
+
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+	if n, ok := lengthOf(value); ok {
+		return n == 0
+	}
+	return reflect.ValueOf(value).IsZero()
+}
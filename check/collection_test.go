@@ -0,0 +1,96 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestLen(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.Len("abc", 3)
+	c.Len([]int{1, 2}, 2)
+	c.Len(map[string]int{"a": 1}, 1)
+	c.Len([3]int{}, 3)
+	c.Len(&[2]int{}, 2)
+	c.Len(make(chan int), 0)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.Len([]int{1, 2}, 3)
+	c.Len(42, 1)
+	if len(mock.errors) != 2 || mock.errors[0] != "Expected length 3, got 2 for [1 2]" ||
+		mock.errors[1] != "Unable to determine the length of int" {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.Empty(nil)
+	c.Empty("")
+	c.Empty([]int{})
+	c.Empty(map[string]int(nil))
+	c.Empty(0)
+	c.Empty(struct{ A int }{})
+	c.NotEmpty("a")
+	c.NotEmpty([]int{0})
+	c.NotEmpty(1)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.Empty([]int{1})
+	c.NotEmpty("")
+	if len(mock.errors) != 2 || mock.errors[0] != "Expected empty, instead got [1]" ||
+		mock.errors[1] != "Expected a non-empty value, instead got " {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestElementsMatch(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.ElementsMatch([]int{1, 2, 2, 3}, []int{2, 3, 1, 2})
+	c.ElementsMatch([]string{}, []string{})
+	c.ElementsMatch([2]string{"a", "b"}, []string{"b", "a"})
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.ElementsMatch([]int{1, 2, 2}, []int{1, 2, 3})
+	c.ElementsMatch(1, []int{1})
+	if len(mock.errors) != 2 ||
+		mock.errors[0] != "Expected elements [1 2 3] to match [1 2 2]; missing [2]; extra [3]" ||
+		mock.errors[1] != "Expected a slice or array, got int" {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestSubset(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.Subset([]int{1, 2, 3}, []int{3, 1})
+	c.Subset([]int{1, 2, 3}, []int{})
+	c.Subset(map[string]int{"a": 1, "b": 2}, map[string]int{"b": 2})
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.Subset([]int{1, 2, 3}, []int{3, 4})
+	c.Subset([]int{1, 2}, []int{1, 1})
+	c.Subset(map[string]int{"a": 1, "b": 2}, map[string]int{"b": 3})
+	if len(mock.errors) != 3 || mock.errors[0] != "Expected [1 2 3] to contain [3 4], but it is missing [4]" ||
+		mock.errors[1] != "Expected [1 2] to contain [1 1], but it is missing [1]" ||
+		mock.errors[2] != `Expected map[a:1 b:2] to contain "b": 3` {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
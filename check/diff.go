@@ -0,0 +1,366 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxDifferences is the maximum number of differences that will be reported.
+const maxDifferences = 50
+
+// DiffOption customizes the comparison performed by Diff().
+type DiffOption func(cfg *diffConfig)
+
+type diffConfig struct {
+	ignorePaths      []*regexp.Regexp
+	ignoreUnexported bool
+}
+
+// IgnoreUnexported returns a DiffOption that causes unexported struct fields to be ignored.
+func IgnoreUnexported() DiffOption {
+	return func(cfg *diffConfig) {
+		cfg.ignoreUnexported = true
+	}
+}
+
+// IgnorePaths returns a DiffOption that causes the values at the specified paths, and anything beneath them, to be
+// ignored. Paths are in the same form as those reported by Diff(), such as ".Items[3].Name". An index or map key of
+// "[*]" matches any index or key.
+func IgnorePaths(paths ...string) DiffOption {
+	return func(cfg *diffConfig) {
+		for _, p := range paths {
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(p), `\[\*\]`, `\[[^\]]*\]`)
+			cfg.ignorePaths = append(cfg.ignorePaths, regexp.MustCompile("^"+pattern+"$"))
+		}
+	}
+}
+
+// WithDiffOptions returns a copy of this Checker that applies the options when comparing values via Equal() and
+// NotEqual().
+func (c Checker) WithDiffOptions(opts ...DiffOption) Checker {
+	c.diffOptions = append(slices.Clip(c.diffOptions), opts...)
+	return c
+}
+
+// Diff returns a description of each difference found by recursively comparing expected and actual, or nil if no
+// differences were found. Each entry starts with the path to the differing value, such as ".Items[3].Name", followed by
+// a description of the difference. Multi-line strings are described with a line-by-line diff.
+func Diff(expected, actual any, opts ...DiffOption) []string {
+	d := differ{visited: make(map[visit]bool)}
+	for _, opt := range opts {
+		opt(&d.cfg)
+	}
+	d.diff("", reflect.ValueOf(expected), reflect.ValueOf(actual))
+	if d.extra > 0 {
+		d.diffs = append(d.diffs, fmt.Sprintf("... and %d more", d.extra))
+	}
+	return d.diffs
+}
+
+type visit struct {
+	a   uintptr
+	b   uintptr
+	typ reflect.Type
+}
+
+type differ struct {
+	visited map[visit]bool
+	diffs   []string
+	cfg     diffConfig
+	extra   int
+}
+
+func (d *differ) report(path, format string, args ...any) {
+	if len(d.diffs) >= maxDifferences {
+		d.extra++
+		return
+	}
+	if path == "" {
+		path = "(root)"
+	}
+	d.diffs = append(d.diffs, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (d *differ) ignored(path string) bool {
+	for _, re := range d.cfg.ignorePaths {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+	if d.ignored(path) {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.report(path, "%s != %s", formatValue(a), formatValue(b))
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.report(path, "type %v != %v", a.Type(), b.Type())
+		return
+	}
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.report(path, "%s != %s", formatValue(a), formatValue(b))
+			}
+			return
+		}
+		if a.Kind() != reflect.Slice || a.Len() != 0 {
+			v := visit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+			if d.visited[v] {
+				return
+			}
+			d.visited[v] = true
+		}
+	default:
+	}
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.Pointer() != b.Pointer() {
+			d.diff(path, a.Elem(), b.Elem())
+		}
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.report(path, "%s != %s", formatValue(a), formatValue(b))
+			}
+			return
+		}
+		d.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		t := a.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if d.cfg.ignoreUnexported && !field.IsExported() {
+				continue
+			}
+			d.diff(path+"."+field.Name, a.Field(i), b.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		common := min(a.Len(), b.Len())
+		for i := range common {
+			d.diff(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i))
+		}
+		for i := common; i < a.Len(); i++ {
+			d.report(path+"["+strconv.Itoa(i)+"]", "%s != <missing>", formatValue(a.Index(i)))
+		}
+		for i := common; i < b.Len(); i++ {
+			d.report(path+"["+strconv.Itoa(i)+"]", "<missing> != %s", formatValue(b.Index(i)))
+		}
+	case reflect.Map:
+		keys := a.MapKeys()
+		for _, k := range b.MapKeys() {
+			if !a.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		slices.SortFunc(keys, func(x, y reflect.Value) int {
+			return strings.Compare(formatValue(x), formatValue(y))
+		})
+		for _, k := range keys {
+			keyPath := path + "[" + formatValue(k) + "]"
+			av := a.MapIndex(k)
+			bv := b.MapIndex(k)
+			switch {
+			case !av.IsValid():
+				if !d.ignored(keyPath) {
+					d.report(keyPath, "<missing> != %s", formatValue(bv))
+				}
+			case !bv.IsValid():
+				if !d.ignored(keyPath) {
+					d.report(keyPath, "%s != <missing>", formatValue(av))
+				}
+			default:
+				d.diff(keyPath, av, bv)
+			}
+		}
+	case reflect.String:
+		as := a.String()
+		bs := b.String()
+		if as != bs {
+			if strings.Contains(as, "\n") || strings.Contains(bs, "\n") {
+				d.report(path, "strings differ (- expected, + actual):\n%s", lineDiff(as, bs))
+			} else {
+				d.report(path, "%q != %q", as, bs)
+			}
+		}
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			d.report(path, "%v != %v", a.Bool(), b.Bool())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			d.report(path, "%s != %s", formatValue(a), formatValue(b))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			d.report(path, "%s != %s", formatValue(a), formatValue(b))
+		}
+	case reflect.Float32, reflect.Float64:
+		if a.Float() != b.Float() {
+			d.report(path, "%s != %s", formatValue(a), formatValue(b))
+		}
+	case reflect.Complex64, reflect.Complex128:
+		if a.Complex() != b.Complex() {
+			d.report(path, "%s != %s", formatValue(a), formatValue(b))
+		}
+	case reflect.Func:
+		// Matches reflect.DeepEqual(), which considers funcs equal only if both are nil
+		if !a.IsNil() || !b.IsNil() {
+			d.report(path, "funcs are only equal when both are nil")
+		}
+	default: // Chan, UnsafePointer
+		if a.Pointer() != b.Pointer() {
+			d.report(path, "%s != %s", formatValue(a), formatValue(b))
+		}
+	}
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return "<nil>"
+		}
+	default:
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// maxLineDiffCells is the maximum number of cells the table used by lineDiff() may have. When the differing portions of
+// the strings would need more than this, only the start of the differences is shown.
+const maxLineDiffCells = 1 << 20
+
+type lineEdit struct {
+	line string
+	op   byte
+}
+
+// lineDiff returns a line-by-line diff of the two strings, showing up to two lines of unchanged context around each
+// change.
+func lineDiff(a, b string) string {
+	const (
+		contextLines  = 2
+		fallbackLines = 5
+	)
+	aLines := strings.Split(a, "\n")
+	bLines := strings.Split(b, "\n")
+	// Lines in common at the start and end don't need to be part of the table
+	prefix := 0
+	for prefix < len(aLines) && prefix < len(bLines) && aLines[prefix] == bLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(aLines)-prefix && suffix < len(bLines)-prefix &&
+		aLines[len(aLines)-1-suffix] == bLines[len(bLines)-1-suffix] {
+		suffix++
+	}
+	aMid := aLines[prefix : len(aLines)-suffix]
+	bMid := bLines[prefix : len(bLines)-suffix]
+	edits := make([]lineEdit, 0, len(aLines)+len(bLines))
+	for _, line := range aLines[:prefix] {
+		edits = append(edits, lineEdit{op: ' ', line: line})
+	}
+	var header string
+	if (len(aMid)+1)*(len(bMid)+1) > maxLineDiffCells {
+		header = fmt.Sprintf("    (too many lines to diff; differences start at line %d)\n", prefix+1)
+		for _, line := range aMid[:min(len(aMid), fallbackLines)] {
+			edits = append(edits, lineEdit{op: '-', line: line})
+		}
+		for _, line := range bMid[:min(len(bMid), fallbackLines)] {
+			edits = append(edits, lineEdit{op: '+', line: line})
+		}
+	} else {
+		edits = appendLineEdits(edits, aMid, bMid)
+		for _, line := range aLines[len(aLines)-suffix:] {
+			edits = append(edits, lineEdit{op: ' ', line: line})
+		}
+	}
+	var buffer strings.Builder
+	buffer.WriteString(header)
+	skipping := false
+	for k, e := range edits {
+		if e.op == ' ' {
+			near := false
+			for n := max(0, k-contextLines); n <= min(len(edits)-1, k+contextLines); n++ {
+				if edits[n].op != ' ' {
+					near = true
+					break
+				}
+			}
+			if !near {
+				if !skipping {
+					buffer.WriteString("    ...\n")
+					skipping = true
+				}
+				continue
+			}
+		}
+		skipping = false
+		buffer.WriteString("    ")
+		buffer.WriteByte(e.op)
+		buffer.WriteByte(' ')
+		buffer.WriteString(e.line)
+		buffer.WriteByte('\n')
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// appendLineEdits appends the edits needed to turn aLines into bLines, using their longest common subsequence.
+func appendLineEdits(edits []lineEdit, aLines, bLines []string) []lineEdit {
+	// Compute the longest common subsequence lengths for the suffixes of each
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(aLines) || j < len(bLines) {
+		switch {
+		case i < len(aLines) && j < len(bLines) && aLines[i] == bLines[j]:
+			edits = append(edits, lineEdit{op: ' ', line: aLines[i]})
+			i++
+			j++
+		case i < len(aLines) && (j == len(bLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, lineEdit{op: '-', line: aLines[i]})
+			i++
+		default:
+			edits = append(edits, lineEdit{op: '+', line: bLines[j]})
+			j++
+		}
+	}
+	return edits
+}
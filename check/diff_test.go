@@ -0,0 +1,163 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+type diffItem struct {
+	Name  string
+	Tags  map[string]int
+	Next  *diffItem
+	id    int
+	Value float64
+}
+
+type diffContainer struct {
+	Title string
+	Items []diffItem
+}
+
+func TestDiffStructPaths(t *testing.T) {
+	expected := diffContainer{
+		Title: "list",
+		Items: []diffItem{{Name: "a"}, {Name: "b", Tags: map[string]int{"x": 1, "y": 2}}, {Name: "c", id: 1}},
+	}
+	actual := diffContainer{
+		Title: "list",
+		Items: []diffItem{{Name: "a"}, {Name: "B", Tags: map[string]int{"x": 1, "z": 3}}, {Name: "c", id: 2}},
+	}
+	diffs := check.Diff(expected, actual)
+	want := []string{
+		`.Items[1].Name: "b" != "B"`,
+		`.Items[1].Tags["y"]: 2 != <missing>`,
+		`.Items[1].Tags["z"]: <missing> != 3`,
+		`.Items[2].id: 1 != 2`,
+	}
+	if strings.Join(diffs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected diffs:\n%s", strings.Join(diffs, "\n"))
+	}
+
+	diffs = check.Diff(expected, actual, check.IgnoreUnexported(), check.IgnorePaths(".Items[*].Tags"))
+	if len(diffs) != 1 || diffs[0] != `.Items[1].Name: "b" != "B"` {
+		t.Errorf("unexpected diffs with options: %v", diffs)
+	}
+}
+
+func TestDiffSlicesAndNil(t *testing.T) {
+	diffs := check.Diff([]int{1, 2}, []int{1, 2, 3})
+	if len(diffs) != 1 || diffs[0] != "[2]: <missing> != 3" {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+	diffs = check.Diff(&diffItem{Name: "a"}, (*diffItem)(nil))
+	if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "(root): ") || !strings.HasSuffix(diffs[0], " != <nil>") {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+	diffs = check.Diff(1, "1")
+	if len(diffs) != 1 || diffs[0] != "(root): type int != string" {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+	if diffs = check.Diff(nil, nil); diffs != nil {
+		t.Errorf("expected no diffs, got %v", diffs)
+	}
+}
+
+func TestDiffCycles(t *testing.T) {
+	a := &diffItem{Name: "a"}
+	a.Next = a
+	b := &diffItem{Name: "a"}
+	b.Next = b
+	if diffs := check.Diff(a, b); diffs != nil {
+		t.Errorf("expected no diffs, got %v", diffs)
+	}
+}
+
+func TestDiffMultilineStrings(t *testing.T) {
+	diffs := check.Diff("one\ntwo\nthree\nfour\nfive\nsix", "one\ntwo\nTHREE\nfour\nfive\nsix")
+	if len(diffs) != 1 {
+		t.Fatalf("unexpected diffs: %v", diffs)
+	}
+	want := "(root): strings differ (- expected, + actual):\n" +
+		"      one\n      two\n    - three\n    + THREE\n      four\n      five\n    ..."
+	if diffs[0] != want {
+		t.Errorf("unexpected diff:\n%s", diffs[0])
+	}
+}
+
+func TestDiffLargeMultilineStrings(t *testing.T) {
+	const count = 10000
+	a := make([]string, count)
+	b := make([]string, count)
+	for i := range count {
+		a[i] = "line " + strconv.Itoa(i)
+		b[i] = "other " + strconv.Itoa(i)
+	}
+	expected := strings.Join(a, "\n")
+
+	// A single change within otherwise identical content is still diffed normally
+	a[count/2] = "changed"
+	diffs := check.Diff(expected, strings.Join(a, "\n"))
+	want := "(root): strings differ (- expected, + actual):\n    ...\n      line 4998\n      line 4999\n" +
+		"    - line 5000\n    + changed\n      line 5001\n      line 5002\n    ..."
+	if len(diffs) != 1 || diffs[0] != want {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+
+	// Content that differs throughout shows only the start of the differences
+	diffs = check.Diff(expected, "line 0\n"+strings.Join(b[1:], "\n"))
+	want = "(root): strings differ (- expected, + actual):\n" +
+		"    (too many lines to diff; differences start at line 2)\n      line 0\n" +
+		"    - line 1\n    - line 2\n    - line 3\n    - line 4\n    - line 5\n" +
+		"    + other 1\n    + other 2\n    + other 3\n    + other 4\n    + other 5"
+	if len(diffs) != 1 || diffs[0] != want {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+}
+
+func TestDiffLimit(t *testing.T) {
+	a := make([]int, 100)
+	b := make([]int, 100)
+	for i := range b {
+		b[i] = i + 1
+	}
+	diffs := check.Diff(a, b)
+	if len(diffs) != 51 || diffs[50] != "... and 50 more" {
+		t.Errorf("unexpected number of diffs: %d", len(diffs))
+	}
+}
+
+func TestEqualReportsDiff(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.Equal(diffContainer{Items: []diffItem{{Name: "a"}}}, diffContainer{Items: []diffItem{{Name: "b"}}})
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "\n  .Items[0].Name: \"a\" != \"b\"") {
+		t.Errorf("unexpected errors: %v", mock.errors)
+	}
+
+	mock = newMockTestingT()
+	c = check.New(mock)
+	c.Equal("a", "b")
+	if len(mock.errors) != 1 || strings.Contains(mock.errors[0], "Differences") {
+		t.Errorf("unexpected errors: %v", mock.errors)
+	}
+
+	mock = newMockTestingT()
+	c = check.New(mock).WithDiffOptions(check.IgnoreUnexported())
+	c.Equal(diffItem{Name: "a", id: 1}, diffItem{Name: "a", id: 2})
+	c.NotEqual(diffItem{Name: "a", id: 1}, diffItem{Name: "b", id: 1})
+	if mock.failed {
+		t.Errorf("unexpected errors: %v", mock.errors)
+	}
+}
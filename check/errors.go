@@ -0,0 +1,91 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+// ErrorIs expects errors.Is(err, target) to be true for err or, if err holds multiple errors accumulated via
+// errs.Append(), for any one of them.
+func (c Checker) ErrorIs(err, target error, msgAndArgs ...any) {
+	c.Helper()
+	if !anyInChain(err, func(e error) bool { return errors.Is(e, target) }) {
+		c.errMsg(fmt.Sprintf("Expected error chain of %s to contain %s", describeError(err), describeError(target)),
+			msgAndArgs...)
+	}
+}
+
+// NotErrorIs expects errors.Is(err, target) to be false for err and, if err holds multiple errors accumulated via
+// errs.Append(), for every one of them.
+func (c Checker) NotErrorIs(err, target error, msgAndArgs ...any) {
+	c.Helper()
+	if anyInChain(err, func(e error) bool { return errors.Is(e, target) }) {
+		c.errMsg(fmt.Sprintf("Expected error chain of %s to not contain %s", describeError(err),
+			describeError(target)), msgAndArgs...)
+	}
+}
+
+// ErrorAs expects errors.As(err, target) to be true for err or, if err holds multiple errors accumulated via
+// errs.Append(), for any one of them. As with errors.As(), target must be a non-nil pointer to either a type that
+// implements error, or to any interface type. On success, target is set to the matching error.
+func (c Checker) ErrorAs(err error, target any, msgAndArgs ...any) {
+	c.Helper()
+	if !anyInChain(err, func(e error) bool { return errors.As(e, target) }) {
+		c.errMsg(fmt.Sprintf("Expected error chain of %s to contain an error assignable to %v", describeError(err),
+			reflect.TypeOf(target).Elem()), msgAndArgs...)
+	}
+}
+
+// anyInChain returns true if matcher returns true for any error in the chain starting with err, including all of the
+// errors accumulated via errs.Append() at any level of the chain.
+func anyInChain(err error, matcher func(e error) bool) bool {
+	for err != nil {
+		if matcher(err) {
+			return true
+		}
+		//nolint:errorlint // Explicitly only want to look at this exact error and not things wrapped inside it
+		switch e := err.(type) {
+		case errs.ErrorWrapper:
+			if e.Count() > 1 {
+				for _, one := range e.WrappedErrors() {
+					if anyInChain(one, matcher) {
+						return true
+					}
+				}
+				return false
+			}
+		case interface{ Unwrap() []error }:
+			for _, one := range e.Unwrap() {
+				if anyInChain(one, matcher) {
+					return true
+				}
+			}
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+func describeError(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	var stackErr errs.StackError
+	if errors.As(err, &stackErr) {
+		return fmt.Sprintf("%q", stackErr.Message())
+	}
+	return fmt.Sprintf("%q", err.Error())
+}
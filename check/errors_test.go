@@ -0,0 +1,57 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func TestErrorIs(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.ErrorIs(fmt.Errorf("wrapped: %w", os.ErrNotExist), os.ErrNotExist)
+	c.ErrorIs(errs.Append(errs.New("first"), os.ErrNotExist), os.ErrNotExist)
+	c.ErrorIs(errs.NewWithCause("outer", errs.Append(errs.New("first"), os.ErrClosed)), os.ErrClosed)
+	c.NotErrorIs(errs.New("other"), os.ErrNotExist)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+
+	c.ErrorIs(errs.New("other"), os.ErrNotExist)
+	c.ErrorIs(nil, os.ErrNotExist, "custom")
+	c.NotErrorIs(errs.Wrap(os.ErrNotExist), os.ErrNotExist)
+	if len(mock.errors) != 3 ||
+		mock.errors[0] != `Expected error chain of "other" to contain "file does not exist"` ||
+		mock.errors[1] != `Expected error chain of <nil> to contain "file does not exist"; custom` ||
+		!strings.Contains(mock.errors[2], "to not contain") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	var target *customError
+	c.ErrorAs(errs.Append(errs.New("first"), &customError{}), &target)
+	if mock.failed || target == nil {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	var pathErr *os.PathError
+	c.ErrorAs(errs.New("other"), &pathErr)
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "assignable to *fs.PathError") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
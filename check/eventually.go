@@ -0,0 +1,96 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"fmt"
+	"time"
+)
+
+// Eventually expects cond to return true within timeout. cond is called immediately and then once every tick until it
+// returns true or the timeout expires. cond is called on the caller's goroutine, so a call that blocks will delay the
+// detection of the timeout.
+func (c Checker) Eventually(cond func() bool, timeout, tick time.Duration, msgAndArgs ...any) {
+	c.Helper()
+	c.EventuallyState(func() (bool, any) { return cond(), nil }, timeout, tick, msgAndArgs...)
+}
+
+// EventuallyState is the same as Eventually(), except cond also returns a description of the state it observed, which
+// is included in the failure message.
+func (c Checker) EventuallyState(cond func() (ok bool, state any), timeout, tick time.Duration, msgAndArgs ...any) {
+	c.Helper()
+	polls, ok, state := poll(cond, timeout, tick)
+	if !ok {
+		msg := fmt.Sprintf("Expected condition to be met within %v, but it was not after %d polls", timeout, polls)
+		if state != nil {
+			msg += fmt.Sprintf("; last observed state: %v", state)
+		}
+		c.errMsg(msg, msgAndArgs...)
+	}
+}
+
+// Never expects cond to not return true within timeout. cond is called immediately and then once every tick until it
+// returns true or the timeout expires. cond is called on the caller's goroutine, so a call that blocks will delay the
+// detection of the timeout.
+func (c Checker) Never(cond func() bool, timeout, tick time.Duration, msgAndArgs ...any) {
+	c.Helper()
+	c.NeverState(func() (bool, any) { return cond(), nil }, timeout, tick, msgAndArgs...)
+}
+
+// NeverState is the same as Never(), except cond also returns a description of the state it observed, which is
+// included in the failure message.
+func (c Checker) NeverState(cond func() (ok bool, state any), timeout, tick time.Duration, msgAndArgs ...any) {
+	c.Helper()
+	polls, ok, state := poll(cond, timeout, tick)
+	if ok {
+		msg := fmt.Sprintf("Expected condition to never be met within %v, but it was on poll %d", timeout, polls)
+		if state != nil {
+			msg += fmt.Sprintf("; observed state: %v", state)
+		}
+		c.errMsg(msg, msgAndArgs...)
+	}
+}
+
+// Receive expects a value to be received from ch within timeout. Returns the value received and true, or the zero
+// value and false if no value could be received.
+func Receive[T any](c Checker, ch <-chan T, timeout time.Duration, msgAndArgs ...any) (value T, ok bool) {
+	c.Helper()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case value, ok = <-ch:
+		if !ok {
+			c.errMsg("Expected to receive a value, but the channel was closed", msgAndArgs...)
+		}
+	case <-timer.C:
+		c.errMsg(fmt.Sprintf("Expected to receive a value within %v, but none arrived", timeout), msgAndArgs...)
+	}
+	return value, ok
+}
+
+// poll calls cond until it returns true or the timeout expires, returning the number of polls made along with the last
+// values returned by cond.
+func poll(cond func() (bool, any), timeout, tick time.Duration) (polls int, ok bool, state any) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(max(tick, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		polls++
+		if ok, state = cond(); ok {
+			return polls, ok, state
+		}
+		select {
+		case <-deadline.C:
+			return polls, ok, state
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestEventually(t *testing.T) {
+	var count atomic.Int32
+	go func() {
+		for range 3 {
+			time.Sleep(time.Millisecond)
+			count.Add(1)
+		}
+	}()
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.Eventually(func() bool { return count.Load() == 3 }, time.Second, time.Millisecond)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+
+	c.EventuallyState(func() (bool, any) { return false, "still waiting" }, 20*time.Millisecond, 5*time.Millisecond)
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "polls; last observed state: still waiting") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.Eventually(func() bool { return false }, 0, time.Millisecond, "custom")
+	if len(mock.errors) != 2 || !strings.Contains(mock.errors[1], "after 1 polls; custom") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestNever(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	polls := 0
+	c.Never(func() bool {
+		polls++
+		return false
+	}, 20*time.Millisecond, 5*time.Millisecond)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	if polls < 2 {
+		t.Errorf("expected multiple polls, got %d", polls)
+	}
+
+	polls = 0
+	c.NeverState(func() (bool, any) {
+		polls++
+		return polls == 2, polls
+	}, time.Second, time.Millisecond)
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "it was on poll 2; observed state: 2") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestReceive(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	ch := make(chan int, 1)
+	go func() { ch <- 42 }()
+	value, ok := check.Receive(c, ch, time.Second)
+	if !ok || value != 42 || mock.failed {
+		t.Fatalf("unexpected result: %d, %v, %v", value, ok, mock.errors)
+	}
+
+	if _, ok = check.Receive(c, ch, 5*time.Millisecond); ok {
+		t.Fatal("expected no value")
+	}
+	close(ch)
+	if _, ok = check.Receive(c, ch, time.Second); ok {
+		t.Fatal("expected no value")
+	}
+	if len(mock.errors) != 2 || !strings.Contains(mock.errors[0], "but none arrived") ||
+		!strings.Contains(mock.errors[1], "the channel was closed") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
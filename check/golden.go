@@ -0,0 +1,146 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"bytes"
+	// Can't use xjson or xyaml for normalization because they depend on xos, which depends on i18n, whose tests use
+	// this package from within the i18n package, so doing so would cause an import cycle.
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateGoldenEnvVar is the environment variable that, when set to a true value such as "1" or "true", causes the
+// Golden methods to rewrite their golden files rather than compare against them. Passing -update-golden to the test
+// binary does the same.
+const UpdateGoldenEnvVar = "UPDATE_GOLDEN"
+
+var updateGolden = flag.Bool("update-golden", false,
+	"rewrite the golden files used by check.Checker.Golden() and friends rather than comparing against them")
+
+// Golden compares actual against the contents of testdata/<name>.golden, relative to the current working directory.
+// If golden file updates have been requested, the golden file is instead rewritten with actual.
+func (c Checker) Golden(name string, actual []byte, msgAndArgs ...any) {
+	c.Helper()
+	c.golden(name, actual, nil, msgAndArgs...)
+}
+
+// GoldenJSON is the same as Golden(), except that both actual and the golden file's contents are normalized as JSON
+// before being compared, so that differences in formatting and key order don't matter.
+func (c Checker) GoldenJSON(name string, actual []byte, msgAndArgs ...any) {
+	c.Helper()
+	c.golden(name, actual, normalizeJSON, msgAndArgs...)
+}
+
+// GoldenYAML is the same as Golden(), except that both actual and the golden file's contents are normalized as YAML
+// before being compared, so that differences in formatting and key order don't matter.
+func (c Checker) GoldenYAML(name string, actual []byte, msgAndArgs ...any) {
+	c.Helper()
+	c.golden(name, actual, normalizeYAML, msgAndArgs...)
+}
+
+func (c Checker) golden(name string, actual []byte, normalizer func([]byte) ([]byte, error), msgAndArgs ...any) {
+	c.Helper()
+	path := filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+	if normalizer != nil {
+		var err error
+		if actual, err = normalizer(actual); err != nil {
+			c.errMsg(fmt.Sprintf("Unable to normalize actual value for golden file %s: %v", path, err), msgAndArgs...)
+			return
+		}
+	}
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			c.errMsg(fmt.Sprintf("Unable to create directory for golden file %s: %v", path, err), msgAndArgs...)
+			return
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			c.errMsg(fmt.Sprintf("Unable to update golden file %s: %v", path, err), msgAndArgs...)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			c.errMsg(fmt.Sprintf("Golden file %s does not exist; run with -update-golden or %s=1 to create it", path,
+				UpdateGoldenEnvVar), msgAndArgs...)
+		} else {
+			c.errMsg(fmt.Sprintf("Unable to read golden file %s: %v", path, err), msgAndArgs...)
+		}
+		return
+	}
+	if normalizer != nil {
+		if expected, err = normalizer(expected); err != nil {
+			c.errMsg(fmt.Sprintf("Unable to normalize golden file %s: %v", path, err), msgAndArgs...)
+			return
+		}
+	}
+	if bytes.Equal(expected, actual) {
+		return
+	}
+	if utf8.Valid(expected) && utf8.Valid(actual) {
+		c.errMsg(fmt.Sprintf("Golden file %s does not match (- expected, + actual):\n%s", path,
+			lineDiff(string(expected), string(actual))), msgAndArgs...)
+		return
+	}
+	i := 0
+	for i < len(expected) && i < len(actual) && expected[i] == actual[i] {
+		i++
+	}
+	c.errMsg(fmt.Sprintf(
+		"Golden file %s does not match: expected %d bytes, got %d bytes, first difference at offset %d",
+		path, len(expected), len(actual), i), msgAndArgs...)
+}
+
+func shouldUpdateGolden() bool {
+	if *updateGolden {
+		return true
+	}
+	update, err := strconv.ParseBool(os.Getenv(UpdateGoldenEnvVar))
+	return err == nil && update
+}
+
+func normalizeJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	normalized, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(normalized, '\n'), nil
+}
+
+func normalizeYAML(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
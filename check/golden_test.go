@@ -0,0 +1,98 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(check.UpdateGoldenEnvVar, "")
+
+	mock := newMockTestingT()
+	check.New(mock).Golden("sub/sample", []byte("line 1\nline 2\n"))
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "does not exist") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+
+	t.Setenv(check.UpdateGoldenEnvVar, "true")
+	mock = newMockTestingT()
+	check.New(mock).Golden("sub/sample", []byte("line 1\nline 2\n"))
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "sub", "sample.golden"))
+	if err != nil || string(data) != "line 1\nline 2\n" {
+		t.Fatalf("golden file not written correctly: %q, %v", data, err)
+	}
+
+	t.Setenv(check.UpdateGoldenEnvVar, "0")
+	mock = newMockTestingT()
+	c := check.New(mock)
+	c.Golden("sub/sample", []byte("line 1\nline 2\n"))
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.Golden("sub/sample", []byte("line 1\nline two\n"))
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "- line 2\n    + line two") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestGoldenBinary(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(check.UpdateGoldenEnvVar, "1")
+	check.New(t).Golden("binary", []byte{0xff, 0x00, 0x01})
+	t.Setenv(check.UpdateGoldenEnvVar, "")
+	mock := newMockTestingT()
+	check.New(mock).Golden("binary", []byte{0xff, 0x00, 0x02, 0x03})
+	if len(mock.errors) != 1 ||
+		!strings.Contains(mock.errors[0], "expected 3 bytes, got 4 bytes, first difference at offset 2") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestGoldenJSON(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(check.UpdateGoldenEnvVar, "1")
+	check.New(t).GoldenJSON("data", []byte(`{"b":1,"a":[1,2]}`))
+	t.Setenv(check.UpdateGoldenEnvVar, "")
+	c := check.New(t)
+	c.GoldenJSON("data", []byte("{\n  \"a\": [1, 2],\n  \"b\": 1\n}"))
+
+	mock := newMockTestingT()
+	c = check.New(mock)
+	c.GoldenJSON("data", []byte(`{"a":[1,3],"b":1}`))
+	c.GoldenJSON("data", []byte(`{`))
+	if len(mock.errors) != 2 || !strings.Contains(mock.errors[0], "does not match") ||
+		!strings.Contains(mock.errors[1], "Unable to normalize actual value") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestGoldenYAML(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(check.UpdateGoldenEnvVar, "1")
+	check.New(t).GoldenYAML("data", []byte("b: 1\na:\n    - 1\n    - 2\n"))
+	t.Setenv(check.UpdateGoldenEnvVar, "")
+	check.New(t).GoldenYAML("data", []byte("a: [1, 2]\nb: 1\n"))
+
+	mock := newMockTestingT()
+	check.New(mock).GoldenYAML("data", []byte("a: [1, 2]\nb: 2\n"))
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "- b: 1\n    + b: 2") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSONEq expects expected and actual to be equivalent JSON documents, ignoring differences in formatting and object key
+// order.
+func (c Checker) JSONEq(expected, actual string, msgAndArgs ...any) {
+	c.Helper()
+	var exp, act any
+	if err := json.Unmarshal([]byte(expected), &exp); err != nil {
+		c.errMsg(fmt.Sprintf("Expected value is not valid JSON: %v", err), msgAndArgs...)
+		return
+	}
+	if err := json.Unmarshal([]byte(actual), &act); err != nil {
+		c.errMsg(fmt.Sprintf("Actual value is not valid JSON: %v", err), msgAndArgs...)
+		return
+	}
+	if diffs := Diff(exp, act); len(diffs) != 0 {
+		c.errMsg(fmt.Sprintf("Expected JSON %s, got %s\nDifferences (expected != actual):\n  %s", expected, actual,
+			strings.Join(diffs, "\n  ")), msgAndArgs...)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestJSONEq(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.JSONEq(`{"a": 1, "b": [true, null]}`, "{\n\"b\":[true,null],\"a\":1.0}")
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.JSONEq(`{"a": 1, "b": [true, null]}`, `{"a": 1, "b": [false, null]}`)
+	c.JSONEq(`{`, `{}`)
+	c.JSONEq(`{}`, `}`)
+	if len(mock.errors) != 3 || !strings.Contains(mock.errors[0], `["b"][0]: true != false`) ||
+		!strings.HasPrefix(mock.errors[1], "Expected value is not valid JSON") ||
+		!strings.HasPrefix(mock.errors[2], "Actual value is not valid JSON") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
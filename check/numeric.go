@@ -0,0 +1,84 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+// InDelta expects the absolute difference between expected and actual to be no more than delta. expected and actual
+// must be of the same type, which may be any integer or floating-point type, geom.Point, geom.Size, geom.Rect, or
+// geom.Insets. For the geom types, each component is checked separately.
+func (c Checker) InDelta(expected, actual any, delta float64, msgAndArgs ...any) {
+	c.Helper()
+	c.withinTolerance(expected, actual, func(e, a float64) bool { return math.Abs(e-a) <= delta },
+		fmt.Sprintf("within %v of", delta), msgAndArgs...)
+}
+
+// InEpsilon expects the relative error between expected and actual, |expected-actual|/|expected|, to be no more than
+// epsilon. When an expected component is zero, the absolute value of the actual component must be no more than epsilon
+// instead. expected and actual must be of the same type, with the same restrictions as for InDelta().
+func (c Checker) InEpsilon(expected, actual any, epsilon float64, msgAndArgs ...any) {
+	c.Helper()
+	c.withinTolerance(expected, actual, func(e, a float64) bool {
+		if e == 0 {
+			return math.Abs(a) <= epsilon
+		}
+		return math.Abs(e-a)/math.Abs(e) <= epsilon
+	}, fmt.Sprintf("within a relative error of %v of", epsilon), msgAndArgs...)
+}
+
+func (c Checker) withinTolerance(expected, actual any, ok func(e, a float64) bool, what string, msgAndArgs ...any) {
+	c.Helper()
+	if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+		c.errMsg(fmt.Sprintf("Expected values of the same type, got %T and %T", expected, actual), msgAndArgs...)
+		return
+	}
+	exp, supported := toFloats(expected)
+	if !supported {
+		c.errMsg(fmt.Sprintf("Unsupported type for tolerance comparison: %T", expected), msgAndArgs...)
+		return
+	}
+	act, _ := toFloats(actual)
+	for i := range exp {
+		if math.IsNaN(exp[i]) || math.IsNaN(act[i]) || !ok(exp[i], act[i]) {
+			c.errMsg(fmt.Sprintf("Expected %v to be %s %v", actual, what, expected), msgAndArgs...)
+			return
+		}
+	}
+}
+
+func toFloats(value any) ([]float64, bool) {
+	switch v := value.(type) {
+	case geom.Point:
+		return []float64{float64(v.X), float64(v.Y)}, true
+	case geom.Size:
+		return []float64{float64(v.Width), float64(v.Height)}, true
+	case geom.Rect:
+		return []float64{float64(v.X), float64(v.Y), float64(v.Width), float64(v.Height)}, true
+	case geom.Insets:
+		return []float64{float64(v.Top), float64(v.Left), float64(v.Bottom), float64(v.Right)}, true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []float64{float64(rv.Int())}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return []float64{float64(rv.Uint())}, true
+	case reflect.Float32, reflect.Float64:
+		return []float64{rv.Float()}, true
+	default:
+		return nil, false
+	}
+}
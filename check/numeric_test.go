@@ -0,0 +1,60 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/geom"
+)
+
+func TestInDelta(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.InDelta(1.0, 1.05, 0.1)
+	c.InDelta(float32(0.3), float32(0.1)+float32(0.2), 1e-6)
+	c.InDelta(10, 12, 2)
+	c.InDelta(geom.NewPoint(1, 2), geom.NewPoint(1.01, 1.99), 0.02)
+	c.InDelta(geom.NewRect(1, 2, 3, 4), geom.NewRect(1, 2, 3.01, 4), 0.02)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+
+	c.InDelta(1.0, 1.2, 0.1)
+	c.InDelta(geom.NewRect(1, 2, 3, 4), geom.NewRect(1, 2, 3, 5), 0.5)
+	c.InDelta(1.0, float32(1), 0.1)
+	c.InDelta("a", "a", 0.1)
+	c.InDelta(math.NaN(), math.NaN(), 1)
+	if len(mock.errors) != 5 ||
+		mock.errors[0] != "Expected 1.2 to be within 0.1 of 1" ||
+		!strings.Contains(mock.errors[1], "to be within 0.5 of") ||
+		mock.errors[2] != "Expected values of the same type, got float64 and float32" ||
+		mock.errors[3] != "Unsupported type for tolerance comparison: string" {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestInEpsilon(t *testing.T) {
+	mock := newMockTestingT()
+	c := check.New(mock)
+	c.InEpsilon(100.0, 101.0, 0.02)
+	c.InEpsilon(0.0, 0.001, 0.01)
+	c.InEpsilon(geom.NewPoint(100, 0), geom.NewPoint(99, 0), 0.02)
+	if mock.failed {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+	c.InEpsilon(100.0, 110.0, 0.02)
+	if len(mock.errors) != 1 || mock.errors[0] != "Expected 110 to be within a relative error of 0.02 of 100" {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/xmath"
+)
+
+const (
+	defaultPropertyIterations = 100
+	maxShrinkSteps            = 1000
+)
+
+// Generator produces random values of a type for property testing, along with simpler variations of a value that are
+// used to shrink a failing value down to a minimal one.
+type Generator[T any] interface {
+	// Generate returns a new random value.
+	Generate(r *rand.Rand) T
+	// Shrink returns candidate values that are simpler than value, ordered from simplest to most complex.
+	Shrink(value T) []T
+}
+
+// PropertyConfig provides configuration for PropertyWithConfig().
+type PropertyConfig struct {
+	// Iterations is the number of random values to test. Zero or less means 100.
+	Iterations int
+	// Seed is the seed for the random number generator. Zero means to use a seed based on the current time. The seed
+	// used is reported on failure, so that the failure can be reproduced.
+	Seed uint64
+}
+
+// Property checks that prop returns true for randomly generated values. If a value is found for which it does not, the
+// value is shrunk to the simplest one that still fails and a failure is reported. A panic within prop is considered a
+// failure.
+func Property[T any](t TestingT, gen Generator[T], prop func(value T) bool) {
+	t.Helper()
+	PropertyWithConfig(t, nil, gen, prop)
+}
+
+// PropertyWithConfig is the same as Property(), but with the ability to configure the number of iterations and the
+// seed. config may be nil.
+func PropertyWithConfig[T any](t TestingT, config *PropertyConfig, gen Generator[T], prop func(value T) bool) {
+	t.Helper()
+	if config == nil {
+		config = &PropertyConfig{}
+	}
+	iterations := config.Iterations
+	if iterations <= 0 {
+		iterations = defaultPropertyIterations
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	r := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // Reproducibility matters here, not security
+	for i := range iterations {
+		value := gen.Generate(r)
+		if holds(prop, value) {
+			continue
+		}
+		shrunk := value
+		steps := 0
+	shrinking:
+		for steps < maxShrinkSteps {
+			for _, candidate := range gen.Shrink(shrunk) {
+				if !holds(prop, candidate) {
+					shrunk = candidate
+					steps++
+					continue shrinking
+				}
+			}
+			break
+		}
+		Checker{TestingT: t}.errMsg(fmt.Sprintf(
+			"Property failed on test %d of %d (seed %d) for %+v; shrunk in %d steps to %+v",
+			i+1, iterations, seed, value, steps, shrunk))
+		return
+	}
+}
+
+func holds[T any](prop func(T) bool, value T) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return prop(value)
+}
+
+type intGenerator[T xmath.Integer] struct {
+	minimum T
+	maximum T
+}
+
+// Ints returns a Generator that produces integers in the range [minimum, maximum], with a bias towards the end points
+// and zero. Values shrink towards zero, or towards whichever end point is closest to zero if zero is not in range.
+func Ints[T xmath.Integer](minimum, maximum T) Generator[T] {
+	if minimum > maximum {
+		minimum, maximum = maximum, minimum
+	}
+	return &intGenerator[T]{minimum: minimum, maximum: maximum}
+}
+
+func (g *intGenerator[T]) Generate(r *rand.Rand) T {
+	if r.IntN(10) == 0 {
+		switch r.IntN(3) {
+		case 0:
+			return g.minimum
+		case 1:
+			return g.maximum
+		default:
+			return g.target()
+		}
+	}
+	span := uint64(g.maximum) - uint64(g.minimum)
+	if span == ^uint64(0) {
+		return T(r.Uint64())
+	}
+	return T(uint64(g.minimum) + r.Uint64N(span+1))
+}
+
+func (g *intGenerator[T]) target() T {
+	switch {
+	case g.minimum > 0:
+		return g.minimum
+	case g.maximum < 0:
+		return g.maximum
+	default:
+		return 0
+	}
+}
+
+func (g *intGenerator[T]) Shrink(value T) []T {
+	target := g.target()
+	if value == target {
+		return nil
+	}
+	candidates := []T{target}
+	// The difference can't overflow, since either the target is zero or both values have the same sign
+	diff := value - target
+	for d := diff / 2; d != 0; d /= 2 {
+		candidates = append(candidates, value-d)
+	}
+	if value > target {
+		candidates = append(candidates, value-1)
+	} else {
+		candidates = append(candidates, value+1)
+	}
+	return candidates
+}
+
+type stringGenerator struct {
+	maxLen int
+}
+
+// Strings returns a Generator that produces strings of up to maxLen runes, drawn mostly from printable ASCII with
+// occasional non-ASCII runes. Values shrink by removing runes and by replacing runes with 'a'.
+func Strings(maxLen int) Generator[string] {
+	return &stringGenerator{maxLen: max(maxLen, 0)}
+}
+
+var nonASCIIRunes = []rune{'é', 'ß', 'π', 'Ж', '中', '文', '😀', '\u200b'}
+
+func (g *stringGenerator) Generate(r *rand.Rand) string {
+	runes := make([]rune, r.IntN(g.maxLen+1))
+	for i := range runes {
+		if r.IntN(10) == 0 {
+			runes[i] = nonASCIIRunes[r.IntN(len(nonASCIIRunes))]
+		} else {
+			runes[i] = rune(' ' + r.IntN('~'-' '+1))
+		}
+	}
+	return string(runes)
+}
+
+func (g *stringGenerator) Shrink(value string) []string {
+	runes := []rune(value)
+	shrunkRunes := shrinkSlice(runes, func(ch rune) []rune {
+		if ch == 'a' {
+			return nil
+		}
+		return []rune{'a'}
+	})
+	candidates := make([]string, len(shrunkRunes))
+	for i, one := range shrunkRunes {
+		candidates[i] = string(one)
+	}
+	return candidates
+}
+
+type sliceGenerator[T any] struct {
+	elem   Generator[T]
+	maxLen int
+}
+
+// SlicesOf returns a Generator that produces slices of up to maxLen elements, each produced by elem. Values shrink by
+// removing elements and by shrinking individual elements.
+func SlicesOf[T any](elem Generator[T], maxLen int) Generator[[]T] {
+	return &sliceGenerator[T]{elem: elem, maxLen: max(maxLen, 0)}
+}
+
+func (g *sliceGenerator[T]) Generate(r *rand.Rand) []T {
+	s := make([]T, r.IntN(g.maxLen+1))
+	for i := range s {
+		s[i] = g.elem.Generate(r)
+	}
+	return s
+}
+
+func (g *sliceGenerator[T]) Shrink(value []T) [][]T {
+	return shrinkSlice(value, g.elem.Shrink)
+}
+
+// shrinkSlice returns candidates that are simpler than s: the empty slice, s with progressively smaller chunks
+// removed, and finally s with a single element shrunk.
+func shrinkSlice[T any](s []T, shrinkElem func(T) []T) [][]T {
+	if len(s) == 0 {
+		return nil
+	}
+	candidates := [][]T{{}}
+	for size := len(s) / 2; size > 0; size /= 2 {
+		for start := 0; start+size <= len(s); start += size {
+			candidate := make([]T, 0, len(s)-size)
+			candidate = append(candidate, s[:start]...)
+			candidates = append(candidates, append(candidate, s[start+size:]...))
+		}
+	}
+	for i, elem := range s {
+		for _, shrunk := range shrinkElem(elem) {
+			candidate := make([]T, len(s))
+			copy(candidate, s)
+			candidate[i] = shrunk
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// Pair holds two values, allowing properties over two inputs to be tested.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+type pairGenerator[A, B any] struct {
+	first  Generator[A]
+	second Generator[B]
+}
+
+// PairsOf returns a Generator that produces pairs of values from the two generators. Values shrink by shrinking the
+// first value, then the second.
+func PairsOf[A, B any](first Generator[A], second Generator[B]) Generator[Pair[A, B]] {
+	return &pairGenerator[A, B]{first: first, second: second}
+}
+
+func (g *pairGenerator[A, B]) Generate(r *rand.Rand) Pair[A, B] {
+	return Pair[A, B]{First: g.first.Generate(r), Second: g.second.Generate(r)}
+}
+
+func (g *pairGenerator[A, B]) Shrink(value Pair[A, B]) []Pair[A, B] {
+	var candidates []Pair[A, B]
+	for _, one := range g.first.Shrink(value.First) {
+		candidates = append(candidates, Pair[A, B]{First: one, Second: value.Second})
+	}
+	for _, one := range g.second.Shrink(value.Second) {
+		candidates = append(candidates, Pair[A, B]{First: value.First, Second: one})
+	}
+	return candidates
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+func TestPropertyHolds(t *testing.T) {
+	check.Property(t, check.PairsOf(check.Ints[int32](-1000, 1000), check.Ints[int32](-1000, 1000)),
+		func(p check.Pair[int32, int32]) bool {
+			return p.First+p.Second-p.Second == p.First
+		})
+	check.Property(t, check.Strings(20), func(s string) bool {
+		return strings.ToUpper(strings.ToUpper(s)) == strings.ToUpper(s)
+	})
+	check.Property(t, check.SlicesOf(check.Ints[uint8](0, math.MaxUint8), 10), func(s []uint8) bool {
+		reversed := slices.Clone(s)
+		slices.Reverse(reversed)
+		slices.Reverse(reversed)
+		return slices.Equal(s, reversed)
+	})
+}
+
+func TestPropertyShrinksIntegers(t *testing.T) {
+	mock := newMockTestingT()
+	check.PropertyWithConfig(mock, &check.PropertyConfig{Seed: 1}, check.Ints(math.MinInt64, int64(math.MaxInt64)),
+		func(v int64) bool { return v < 100 })
+	if len(mock.errors) != 1 || !strings.HasSuffix(mock.errors[0], " to 100") ||
+		!strings.Contains(mock.errors[0], "(seed 1)") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+
+	mock = newMockTestingT()
+	check.PropertyWithConfig(mock, &check.PropertyConfig{Seed: 1}, check.Ints(10, 20),
+		func(v int) bool { return v < 15 })
+	if len(mock.errors) != 1 || !strings.HasSuffix(mock.errors[0], " to 15") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestPropertyShrinksStringsAndSlices(t *testing.T) {
+	mock := newMockTestingT()
+	check.PropertyWithConfig(mock, &check.PropertyConfig{Seed: 1, Iterations: 1000}, check.Strings(30),
+		func(s string) bool { return !strings.ContainsRune(s, 'a') && len([]rune(s)) < 5 })
+	if len(mock.errors) != 1 || !(strings.HasSuffix(mock.errors[0], " to a") ||
+		strings.HasSuffix(mock.errors[0], " to aaaaa")) {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+
+	mock = newMockTestingT()
+	check.PropertyWithConfig(mock, &check.PropertyConfig{Seed: 1, Iterations: 1000},
+		check.SlicesOf(check.Ints(0, 100), 20), func(s []int) bool { return slices.Index(s, 0) == -1 || len(s) < 3 })
+	if len(mock.errors) != 1 || !strings.HasSuffix(mock.errors[0], " to [0 0 0]") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
+
+func TestPropertyPanicIsFailure(t *testing.T) {
+	mock := newMockTestingT()
+	check.Property(mock, check.Ints(1, 10), func(v int) bool {
+		return 10/(v-v) == 0
+	})
+	if len(mock.errors) != 1 || !strings.HasSuffix(mock.errors[0], " to 1") {
+		t.Fatalf("unexpected errors: %v", mock.errors)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// Run executes fn as a subtest for each of the cases. Subtests are named using the case's Name or name string field,
+// if it has one, its String() method, if it implements fmt.Stringer, or its index otherwise. When a subtest fails, the
+// fields of its case are logged.
+func Run[C any](t *testing.T, cases []C, fn func(c Checker, tc C)) {
+	t.Helper()
+	run(t, cases, fn, false)
+}
+
+// RunParallel is the same as Run(), except that the subtests are run in parallel with each other.
+func RunParallel[C any](t *testing.T, cases []C, fn func(c Checker, tc C)) {
+	t.Helper()
+	run(t, cases, fn, true)
+}
+
+func run[C any](t *testing.T, cases []C, fn func(c Checker, tc C), parallel bool) {
+	t.Helper()
+	for i, tc := range cases {
+		t.Run(caseName(i, tc), func(t *testing.T) {
+			t.Helper()
+			if parallel {
+				t.Parallel()
+			}
+			defer func() {
+				if t.Failed() {
+					t.Logf("case %d: %+v", i, tc)
+				}
+			}()
+			fn(New(t), tc)
+		})
+	}
+}
+
+func caseName(index int, tc any) string {
+	v := reflect.ValueOf(tc)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		for _, name := range []string{"Name", "name"} {
+			if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+				return f.String()
+			}
+		}
+	}
+	if s, ok := tc.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return "case_" + strconv.Itoa(index)
+}
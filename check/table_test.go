@@ -0,0 +1,60 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package check_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+)
+
+type namedCase struct {
+	Name     string
+	Input    int
+	Expected int
+}
+
+type stringerCase int
+
+func (s stringerCase) String() string {
+	return "stringer"
+}
+
+func TestRun(t *testing.T) {
+	var names []string
+	check.Run(t, []namedCase{
+		{Name: "double one", Input: 1, Expected: 2},
+		{Name: "double two", Input: 2, Expected: 4},
+		{Input: 3, Expected: 6},
+	}, func(c check.Checker, tc namedCase) {
+		names = append(names, c.Name())
+		c.Equal(tc.Expected, tc.Input*2)
+	})
+	check.Run(t, []stringerCase{1}, func(c check.Checker, _ stringerCase) {
+		names = append(names, c.Name())
+	})
+	c := check.New(t)
+	c.Equal([]string{"TestRun/double_one", "TestRun/double_two", "TestRun/case_2", "TestRun/stringer"}, names)
+}
+
+func TestRunParallel(t *testing.T) {
+	var lock sync.Mutex
+	seen := make(map[int]bool)
+	t.Run("group", func(t *testing.T) {
+		check.RunParallel(t, []namedCase{{Name: "a", Input: 1}, {Name: "b", Input: 2}, {Name: "c", Input: 3}},
+			func(_ check.Checker, tc namedCase) {
+				lock.Lock()
+				seen[tc.Input] = true
+				lock.Unlock()
+			})
+	})
+	check.New(t).Equal(map[int]bool{1: true, 2: true, 3: true}, seen)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// WithAttrs returns an Option that attaches the attributes to an Error.
+func WithAttrs(attrs ...slog.Attr) Option {
+	return func(e *Error) {
+		e.attrs = append(slices.Clip(e.attrs), attrs...)
+	}
+}
+
+// With returns a copy of this error with the attributes described by args attached to it. args are interpreted in the
+// same manner as slog.Logger.Log() interprets them. The attributes will be emitted whenever the error is logged.
+func (e *Error) With(args ...any) *Error {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return e.WithAttrs(attrs...)
+}
+
+// WithAttrs returns a copy of this error with the attributes attached to it. The attributes will be emitted whenever
+// the error is logged.
+func (e *Error) WithAttrs(attrs ...slog.Attr) *Error {
+	var revised Error
+	if e != nil {
+		revised = *e
+	}
+	revised.attrs = append(slices.Clip(revised.attrs), attrs...)
+	return &revised
+}
+
+// Attrs returns the attributes attached directly to this error. Use AttrsOf() to also collect the attributes attached
+// to the chain of causes.
+func (e *Error) Attrs() []slog.Attr {
+	if e == nil {
+		return nil
+	}
+	return slices.Clone(e.attrs)
+}
+
+// AttrsOf returns the attributes attached to all errors found by walking the chain of errors starting with err. When
+// more than one error in the chain has an attribute with the same key, only the outermost one is returned.
+func AttrsOf(err error) []slog.Attr {
+	var attrs []slog.Attr
+	seen := make(map[string]struct{})
+	walk(err, func(e *Error) bool {
+		for _, attr := range e.attrs {
+			if _, exists := seen[attr.Key]; !exists {
+				seen[attr.Key] = struct{}{}
+				attrs = append(attrs, attr)
+			}
+		}
+		return true
+	})
+	return attrs
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs_test
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func TestWith(t *testing.T) {
+	c := check.New(t)
+	original := errs.New("save failed")
+	err := original.With("path", "/tmp/x", "user", "bob")
+	c.Equal(0, len(original.Attrs()))
+	attrs := err.Attrs()
+	c.Equal(2, len(attrs))
+	c.Equal("path", attrs[0].Key)
+	c.Equal("/tmp/x", attrs[0].Value.String())
+	c.Equal("user", attrs[1].Key)
+	c.Equal("bob", attrs[1].Value.String())
+	c.Equal(original.RawStackTrace(), err.RawStackTrace())
+
+	more := err.WithAttrs(slog.Int("count", 3))
+	c.Equal(2, len(err.Attrs()))
+	c.Equal(3, len(more.Attrs()))
+
+	opt := errs.New("opt", errs.WithAttrs(slog.Bool("flag", true)))
+	c.Equal(1, len(opt.Attrs()))
+}
+
+func TestAttrsOf(t *testing.T) {
+	c := check.New(t)
+	inner := errs.New("inner").With("path", "/inner", "depth", 2)
+	outer := errs.NewWithCause("outer", fmt.Errorf("middle: %w", inner)).With("path", "/outer")
+	attrs := errs.AttrsOf(outer)
+	c.Equal(2, len(attrs))
+	c.Equal("path", attrs[0].Key)
+	c.Equal("/outer", attrs[0].Value.String())
+	c.Equal("depth", attrs[1].Key)
+	c.Nil(errs.AttrsOf(nil))
+
+	// Attributes of appended errors are found, too
+	attrs = errs.AttrsOf(errs.Append(errs.New("first"), inner))
+	c.Equal(2, len(attrs))
+	c.Equal("/inner", attrs[0].Value.String())
+}
+
+func TestLogIncludesErrorAttrs(t *testing.T) {
+	c := check.New(t)
+	logger, catcher := newLoggerWithCatcher()
+	inner := errs.New("inner").With("path", "/tmp/x")
+	errs.LogTo(logger, errs.NewWithCause("outer", inner), "site", "top")
+	c.Equal(1, len(catcher.records))
+	found := make(map[string]string)
+	catcher.records[0].Attrs(func(a slog.Attr) bool {
+		found[a.Key] = a.Value.String()
+		return true
+	})
+	c.Equal("/tmp/x", found["path"])
+	c.Equal("top", found["site"])
+
+	var groupFound bool
+	for _, attr := range inner.LogValue().Group() {
+		if attr.Key == "path" {
+			groupFound = true
+		}
+	}
+	c.True(groupFound)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs
+
+import "errors"
+
+// Common error codes. These are provided as a convenience; any string may be used as a code.
+const (
+	CodeCanceled         = "canceled"
+	CodeConflict         = "conflict"
+	CodeInternal         = "internal"
+	CodeInvalidArgument  = "invalid_argument"
+	CodeNotFound         = "not_found"
+	CodePermissionDenied = "permission_denied"
+	CodeTimeout          = "timeout"
+	CodeUnavailable      = "unavailable"
+)
+
+// Option is used to configure an Error at creation time.
+type Option func(e *Error)
+
+// WithCode returns an Option that sets the machine-readable code of an Error.
+func WithCode(code string) Option {
+	return func(e *Error) {
+		e.code = code
+	}
+}
+
+// WithCategory returns an Option that sets the category of an Error.
+func WithCategory(category string) Option {
+	return func(e *Error) {
+		e.category = category
+	}
+}
+
+// Code returns the machine-readable code attached to this error, if any. Note that only the code of this error is
+// returned; use CodeOf() to also search through the chain of causes.
+func (e *Error) Code() string {
+	if e == nil {
+		return ""
+	}
+	return e.code
+}
+
+// Category returns the category attached to this error, if any. Note that only the category of this error is
+// returned; use CategoryOf() to also search through the chain of causes.
+func (e *Error) Category() string {
+	if e == nil {
+		return ""
+	}
+	return e.category
+}
+
+// CodeOf returns the first non-empty code found by walking the chain of errors starting with err, or an empty string
+// if none is found.
+func CodeOf(err error) string {
+	return find(err, func(e *Error) string { return e.code })
+}
+
+// CategoryOf returns the first non-empty category found by walking the chain of errors starting with err, or an empty
+// string if none is found.
+func CategoryOf(err error) string {
+	return find(err, func(e *Error) string { return e.category })
+}
+
+// HasCode returns true if CodeOf(err) would return the specified code.
+func HasCode(err error, code string) bool {
+	return CodeOf(err) == code
+}
+
+func find(err error, extractor func(e *Error) string) string {
+	var result string
+	walk(err, func(e *Error) bool {
+		result = extractor(e)
+		return result == ""
+	})
+	return result
+}
+
+// walk calls visitor for each *Error found in the chain of errors starting with err, stopping early if visitor returns
+// false. For an *Error, its chain of causes is visited before any errors that were appended to it. Returns false if the
+// walk was stopped early.
+func walk(err error, visitor func(e *Error) bool) bool {
+	for err != nil {
+		//nolint:errorlint // Explicitly only want to look at this exact error and not things wrapped inside it
+		switch e := err.(type) {
+		case *Error:
+			if e == nil {
+				return true
+			}
+			if !visitor(e) || !walk(e.cause, visitor) {
+				return false
+			}
+			if e.next == nil {
+				return true
+			}
+			err = e.next
+			continue
+		case interface{ Unwrap() []error }:
+			for _, one := range e.Unwrap() {
+				if !walk(one, visitor) {
+					return false
+				}
+			}
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return true
+}
+
+func (e *Error) apply(opts []Option) *Error {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+	return e
+}
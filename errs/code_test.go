@@ -0,0 +1,92 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func TestCode(t *testing.T) {
+	c := check.New(t)
+	err := errs.New("missing", errs.WithCode(errs.CodeNotFound), errs.WithCategory("client"))
+	c.Equal(errs.CodeNotFound, err.Code())
+	c.Equal("client", err.Category())
+	c.Equal(errs.CodeNotFound, errs.CodeOf(err))
+	c.Equal("client", errs.CategoryOf(err))
+	c.True(errs.HasCode(err, errs.CodeNotFound))
+	c.False(errs.HasCode(err, errs.CodeConflict))
+
+	var nilErr *errs.Error
+	c.Equal("", nilErr.Code())
+	c.Equal("", nilErr.Category())
+	c.Equal("", errs.CodeOf(nilErr))
+	c.Equal("", errs.CodeOf(nil))
+	c.Equal("", errs.CodeOf(os.ErrNotExist))
+}
+
+func TestCodeSurvivesWrapping(t *testing.T) {
+	c := check.New(t)
+	err := errs.New("missing", errs.WithCode(errs.CodeNotFound))
+
+	c.Equal(errs.CodeNotFound, errs.CodeOf(fmt.Errorf("outer: %w", err)))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errors.Join(os.ErrClosed, err)))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errs.NewWithCause("outer", err)))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errs.Append(err, errs.New("other"))))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errs.Append(nil, err)))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errs.Append(errs.New("x"), err)))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errs.Append(errs.New("x"), errs.New("y"), err)))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(fmt.Errorf("outer: %w", errs.Append(errs.New("x"), err))))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(err.CloneWithPrefixMessage("prefix: ")))
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errs.Wrap(err)))
+
+	outer := errs.NewWithCause("outer", err, errs.WithCode(errs.CodeInternal))
+	c.Equal(errs.CodeInternal, errs.CodeOf(outer))
+}
+
+func TestWrapWithOptions(t *testing.T) {
+	c := check.New(t)
+	wrapped := errs.WrapTyped(os.ErrNotExist, errs.WithCode(errs.CodeNotFound))
+	c.Equal(errs.CodeNotFound, wrapped.Code())
+	c.True(errors.Is(wrapped, os.ErrNotExist))
+
+	c.True(errs.HasCode(errs.Wrap(os.ErrNotExist, errs.WithCode(errs.CodeNotFound)), errs.CodeNotFound))
+
+	original := errs.New("conflict")
+	revised := errs.WrapTyped(original, errs.WithCode(errs.CodeConflict))
+	c.Equal("", original.Code())
+	c.Equal(errs.CodeConflict, revised.Code())
+	c.Equal(original.RawStackTrace(), revised.RawStackTrace())
+
+	c.Equal(errs.CodeConflict, errs.CodeOf(errs.Wrap(original, errs.WithCode(errs.CodeConflict))))
+	c.Equal("", original.Code())
+}
+
+func TestCodeLogValue(t *testing.T) {
+	c := check.New(t)
+	err := errs.New("missing", errs.WithCode(errs.CodeNotFound), errs.WithCategory("client"))
+	found := 0
+	for _, attr := range err.LogValue().Group() {
+		switch attr.Key {
+		case errs.CodeKey:
+			c.Equal(errs.CodeNotFound, attr.Value.String())
+			found++
+		case errs.CategoryKey:
+			c.Equal("client", attr.Value.String())
+			found++
+		}
+	}
+	c.Equal(2, found)
+}
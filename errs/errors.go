@@ -46,11 +46,15 @@ type StackError interface {
 
 // Error holds the detailed error message.
 type Error struct {
-	cause   error
-	next    *Error
-	message string
-	stack   []uintptr
-	wrapped bool
+	cause    error
+	next     *Error
+	message  string
+	code     string
+	category string
+	attrs    []slog.Attr
+	stack    []uintptr
+	frames   []string
+	wrapped  bool
 }
 
 // CloneWithPrefixMessage clones this error and adds a prefix to its message.
@@ -61,26 +65,36 @@ func (e *Error) CloneWithPrefixMessage(prefix string) error {
 }
 
 // Wrap an error and turn it into a detailed error. If error is already a detailed error or nil, it will be returned
-// as-is.
-func Wrap(cause error) error {
+// as-is, unless options were provided, in which case a detailed error will be returned with the options applied to a
+// copy of it.
+func Wrap(cause error, opts ...Option) error {
 	if xreflect.IsNil(cause) {
 		return nil
 	}
-	var errorPtr *Error
-	if errors.As(cause, &errorPtr) {
-		return cause
+	if len(opts) != 0 {
+		//nolint:errorlint // Explicitly only want to look at this exact error and not things wrapped inside it
+		if err, ok := cause.(*Error); ok {
+			revised := *err
+			return revised.apply(opts)
+		}
+	} else {
+		var errorPtr *Error
+		if errors.As(cause, &errorPtr) {
+			return cause
+		}
 	}
-	return &Error{
+	return (&Error{
 		message: cause.Error(),
 		stack:   callStack(),
 		cause:   cause,
 		wrapped: true,
-	}
+	}).apply(opts)
 }
 
 // WrapTyped wraps an error and turns it into a detailed error. If error is already a detailed error or nil, it will be
-// returned as-is. This method returns the error as an *Error. Use Wrap() to receive a generic error.
-func WrapTyped(cause error) *Error {
+// returned as-is, unless options were provided, in which case the options will be applied to a copy of it. This method
+// returns the error as an *Error. Use Wrap() to receive a generic error.
+func WrapTyped(cause error, opts ...Option) *Error {
 	if xreflect.IsNil(cause) {
 		return nil
 	}
@@ -88,22 +102,26 @@ func WrapTyped(cause error) *Error {
 	// to avoid losing information and still return an *Error
 	//nolint:errorlint // See note above
 	if err, ok := cause.(*Error); ok {
-		return err
+		if len(opts) == 0 {
+			return err
+		}
+		revised := *err
+		return revised.apply(opts)
 	}
-	return &Error{
+	return (&Error{
 		message: cause.Error(),
 		stack:   callStack(),
 		cause:   cause,
 		wrapped: true,
-	}
+	}).apply(opts)
 }
 
 // New creates a new detailed error with the 'message'.
-func New(message string) *Error {
-	return &Error{
+func New(message string, opts ...Option) *Error {
+	return (&Error{
 		message: message,
 		stack:   callStack(),
-	}
+	}).apply(opts)
 }
 
 // Newf creates a new detailed error using fmt.Sprintf() to format the message.
@@ -112,12 +130,12 @@ func Newf(format string, v ...any) *Error {
 }
 
 // NewWithCause creates a new detailed error with the 'message' and underlying 'cause'.
-func NewWithCause(message string, cause error) *Error {
-	return &Error{
+func NewWithCause(message string, cause error, opts ...Option) *Error {
+	return (&Error{
 		message: message,
 		stack:   callStack(),
 		cause:   cause,
-	}
+	}).apply(opts)
 }
 
 // NewWithCausef creates a new detailed error with an underlying 'cause' and using fmt.Sprintf() to format the message.
@@ -229,11 +247,22 @@ func (e *Error) Error() string {
 }
 
 // Detail returns the fully detailed error message, which includes the primary message, the call stack, and potentially
-// one or more chained causes. Note that any included stack trace will be only for the first error in the case where
+// one or more chained causes. The stack traces are rendered according to DefaultStackPolicy. Note that unless the
+// policy's IncludeAppended field is true, any included stack trace will be only for the first error in the case where
 // multiple errors were accumulated into one via calls to .Append().
 func (e *Error) Detail() string {
+	return e.detail(&DefaultStackPolicy, nil)
+}
+
+// DetailWithPolicy returns the same information as Detail(), but with the stack traces rendered according to the
+// provided policy rather than DefaultStackPolicy.
+func (e *Error) DetailWithPolicy(policy StackPolicy) string {
+	return e.detail(&policy, nil)
+}
+
+func (e *Error) detail(policy *StackPolicy, enclosing []string) string {
 	msg := e.Message()
-	stack := e.StackTrace()
+	stack := e.stackTrace(policy, enclosing)
 	switch {
 	case msg == "" && stack == "":
 		return "<no detail>"
@@ -246,14 +275,40 @@ func (e *Error) Detail() string {
 	}
 }
 
-// StackTrace returns just the stack trace portion of the message.
+// StackTrace returns just the stack trace portion of the message, rendered according to DefaultStackPolicy.
 func (e *Error) StackTrace() string {
+	return e.stackTrace(&DefaultStackPolicy, nil)
+}
+
+// StackTraceWithPolicy returns just the stack trace portion of the message, rendered according to the provided policy
+// rather than DefaultStackPolicy.
+func (e *Error) StackTraceWithPolicy(policy StackPolicy) string {
+	return e.stackTrace(&policy, nil)
+}
+
+func (e *Error) stackTrace(policy *StackPolicy, enclosing []string) string {
 	var buffer strings.Builder
-	stack := strings.Join(xruntime.PCsToStackTrace(e.stack), "\n    ")
-	if stack != "" {
-		buffer.WriteString("    ")
+	lines := e.writeSingleStackTrace(&buffer, policy, enclosing)
+	if policy.IncludeAppended {
+		for next := e.next; next != nil; next = next.next {
+			if buffer.Len() != 0 {
+				buffer.WriteByte('\n')
+			}
+			buffer.WriteString("  Also: ")
+			single := *next
+			single.next = nil
+			buffer.WriteString(single.detail(policy, lines))
+			lines = policy.apply(single.stackLines())
+		}
 	}
-	buffer.WriteString(stack)
+	return buffer.String()
+}
+
+// writeSingleStackTrace writes the stack trace and causes for just this error, ignoring any errors appended to it.
+// Returns the filtered stack lines for this error.
+func (e *Error) writeSingleStackTrace(buffer *strings.Builder, policy *StackPolicy, enclosing []string) []string {
+	lines := policy.apply(e.stackLines())
+	policy.write(buffer, lines, enclosing)
 	if e.cause != nil && !e.wrapped {
 		if buffer.Len() != 0 {
 			buffer.WriteByte('\n')
@@ -261,12 +316,21 @@ func (e *Error) StackTrace() string {
 		buffer.WriteString("  Caused by: ")
 		//nolint:errorlint // Explicitly only want to look at this exact error and not things wrapped inside it
 		if detailed, ok := e.cause.(*Error); ok {
-			buffer.WriteString(detailed.Detail())
+			buffer.WriteString(detailed.detail(policy, lines))
 		} else {
 			buffer.WriteString(e.cause.Error())
 		}
 	}
-	return buffer.String()
+	return lines
+}
+
+// stackLines returns the symbolized stack trace. Errors that were rehydrated from a serialized form will not have the
+// original program counters available, so the previously symbolized frames are returned for them instead.
+func (e *Error) stackLines() []string {
+	if len(e.stack) == 0 {
+		return e.frames
+	}
+	return xruntime.PCsToStackTrace(e.stack)
 }
 
 // RawStackTrace returns the raw call stack pointers for the first error within this error.
@@ -283,7 +347,8 @@ func (e *Error) ErrorOrNil() error {
 }
 
 func (e *Error) empty() bool {
-	return e == nil || (e.message == "" && e.stack == nil && e.cause == nil && e.next == nil)
+	return e == nil || (e.message == "" && e.stack == nil && e.frames == nil && e.cause == nil && e.next == nil &&
+		e.code == "" && e.category == "" && len(e.attrs) == 0)
 }
 
 // WrappedErrors returns the contained errors.
@@ -323,8 +388,19 @@ func (e *Error) Format(state fmt.State, verb rune) {
 
 // LogValue implements the slog.LogValuer interface.
 func (e *Error) LogValue() slog.Value {
-	return slog.GroupValue(
-		slog.String("error", e.Message()),
-		slog.Any(StackTraceKey, &stackValue{err: e}),
-	)
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs, slog.String("error", e.Message()))
+	attrs = e.appendContextAttrs(attrs)
+	attrs = append(attrs, slog.Any(StackTraceKey, &stackValue{err: e}))
+	return slog.GroupValue(attrs...)
+}
+
+func (e *Error) appendContextAttrs(attrs []slog.Attr) []slog.Attr {
+	if e.code != "" {
+		attrs = append(attrs, slog.String(CodeKey, e.code))
+	}
+	if e.category != "" {
+		attrs = append(attrs, slog.String(CategoryKey, e.category))
+	}
+	return append(attrs, AttrsOf(e)...)
 }
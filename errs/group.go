@@ -0,0 +1,145 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// GroupConfig provides configuration for a Group.
+type GroupConfig struct {
+	// Limit is the maximum number of functions that may be running at once. Calls to Go() will block when this number
+	// of functions are already running. Zero or less means no limit.
+	Limit int
+	// CancelOnError causes the Group's context to be canceled as soon as any function returns an error or panics.
+	CancelOnError bool
+}
+
+// Group runs functions concurrently and collects the errors they return, along with any panics they trigger. It is
+// similar in spirit to golang.org/x/sync/errgroup, but accumulates all errors rather than just the first one.
+type Group struct {
+	ctx           context.Context
+	cancel        context.CancelCauseFunc
+	sem           chan struct{}
+	err           *Error
+	wg            sync.WaitGroup
+	lock          sync.Mutex
+	cancelOnError bool
+	skipped       bool
+	failed        bool
+}
+
+// NewGroup creates a new Group whose context is derived from ctx. config may be nil.
+func NewGroup(ctx context.Context, config *GroupConfig) *Group {
+	if config == nil {
+		config = &GroupConfig{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	g := &Group{cancelOnError: config.CancelOnError}
+	g.ctx, g.cancel = context.WithCancelCause(ctx)
+	if config.Limit > 0 {
+		g.sem = make(chan struct{}, config.Limit)
+	}
+	return g
+}
+
+// Context returns the context that is passed to the functions run by this Group. It is canceled when Wait() returns,
+// when the parent context is canceled, or, if CancelOnError was set, when the first error occurs.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go calls f in a new goroutine. If a concurrency limit was set, this will block until f can be started. If the
+// Group's context is done before f can be started, f will not be called and the context's cause will be included in
+// the result of Wait(). A panic within f is recovered and turned into an error, just as xos.PanicRecovery() does.
+func (g *Group) Go(f func(ctx context.Context) error) {
+	if g.ctx.Err() != nil {
+		g.skip()
+		return
+	}
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.skip()
+			return
+		}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		defer g.recover()
+		if err := f(g.ctx); err != nil {
+			g.add(err)
+		}
+	}()
+}
+
+// Wait blocks until all functions started via Go() have returned, then returns the accumulated errors, if any. The
+// Group should not be reused after Wait() returns.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	// When canceled due to CancelOnError, the cause is already present in the accumulated errors
+	if g.skipped && !(g.cancelOnError && g.failed) {
+		g.err = Append(g.err, context.Cause(g.ctx))
+	}
+	g.skipped = false
+	g.cancel(nil)
+	return g.err.ErrorOrNil()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+func (g *Group) skip() {
+	g.lock.Lock()
+	g.skipped = true
+	g.lock.Unlock()
+}
+
+func (g *Group) add(err error) {
+	g.lock.Lock()
+	g.err = Append(g.err, err)
+	g.failed = true
+	g.lock.Unlock()
+	if g.cancelOnError {
+		g.cancel(err)
+	}
+}
+
+// recover must be called directly via defer, so that the stack trace it captures is that of the panic.
+func (g *Group) recover() {
+	if recovered := recover(); recovered != nil {
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("%+v", recovered)
+		}
+		var pcs [128]uintptr
+		n := runtime.Callers(2, pcs[:])
+		stack := make([]uintptr, n)
+		copy(stack, pcs[:n])
+		g.add(&Error{
+			message: "recovered from panic",
+			stack:   stack,
+			cause:   err,
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func TestGroupNoErrors(t *testing.T) {
+	c := check.New(t)
+	g := errs.NewGroup(context.Background(), nil)
+	var count atomic.Int32
+	for range 10 {
+		g.Go(func(_ context.Context) error {
+			count.Add(1)
+			return nil
+		})
+	}
+	c.NoError(g.Wait())
+	c.Equal(int32(10), count.Load())
+	c.NotNil(g.Context().Err())
+}
+
+func TestGroupAccumulatesErrors(t *testing.T) {
+	c := check.New(t)
+	g := errs.NewGroup(context.Background(), nil)
+	for i := range 5 {
+		g.Go(func(_ context.Context) error {
+			if i%2 == 0 {
+				return errs.Newf("failed %d", i)
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+	c.HasError(err)
+	var e *errs.Error
+	c.True(errors.As(err, &e))
+	c.Equal(3, e.Count())
+}
+
+func panicker() {
+	var bad *int
+	_ = *bad //nolint:govet // Intentionally triggering a panic
+}
+
+func TestGroupRecoversPanics(t *testing.T) {
+	c := check.New(t)
+	g := errs.NewGroup(context.Background(), nil)
+	g.Go(func(_ context.Context) error {
+		panicker()
+		return nil
+	})
+	g.Go(func(_ context.Context) error {
+		panic("plain value")
+	})
+	var e *errs.Error
+	c.True(errors.As(g.Wait(), &e))
+	c.Equal(2, e.Count())
+	var buffer strings.Builder
+	for _, one := range e.WrappedErrors() {
+		buffer.WriteString(one.Error())
+	}
+	detail := buffer.String()
+	c.Contains(detail, "recovered from panic")
+	c.Contains(detail, "errs_test.panicker]")
+	c.Contains(detail, "Caused by: runtime error: invalid memory address or nil pointer dereference")
+	c.Contains(detail, "Caused by: plain value")
+}
+
+func TestGroupLimit(t *testing.T) {
+	c := check.New(t)
+	g := errs.NewGroup(context.Background(), &errs.GroupConfig{Limit: 2})
+	var running, maxRunning atomic.Int32
+	block := make(chan struct{})
+	go func() {
+		for running.Load() < 2 {
+			runtime.Gosched()
+		}
+		close(block)
+	}()
+	for range 6 {
+		g.Go(func(_ context.Context) error {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			<-block
+			running.Add(-1)
+			return nil
+		})
+	}
+	c.NoError(g.Wait())
+	c.True(maxRunning.Load() <= 2)
+}
+
+func TestGroupCancelOnError(t *testing.T) {
+	c := check.New(t)
+	g := errs.NewGroup(context.Background(), &errs.GroupConfig{CancelOnError: true, Limit: 1})
+	g.Go(func(_ context.Context) error {
+		return errs.New("first")
+	})
+	<-g.Context().Done()
+	var ran atomic.Bool
+	g.Go(func(_ context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+	err := g.Wait()
+	c.False(ran.Load())
+	var e *errs.Error
+	c.True(errors.As(err, &e))
+	c.Equal(1, e.Count())
+}
+
+func TestGroupParentCanceled(t *testing.T) {
+	c := check.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g := errs.NewGroup(ctx, nil)
+	var ran atomic.Bool
+	g.Go(func(_ context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+	err := g.Wait()
+	c.False(ran.Load())
+	c.True(errors.Is(err, context.Canceled))
+}
@@ -18,8 +18,12 @@ import (
 	"time"
 )
 
-// StackTraceKey is the key used for logging the stack trace.
-const StackTraceKey = "stack"
+// Keys used for logging.
+const (
+	StackTraceKey = "stack"
+	CodeKey       = "code"
+	CategoryKey   = "category"
+)
 
 // Log an error with a stack trace.
 func Log(err error, args ...any) {
@@ -72,6 +76,7 @@ func createRecord(level slog.Level, err *Error) slog.Record {
 	}
 	r := slog.NewRecord(time.Now(), level, msg, pc)
 	if err != nil {
+		r.AddAttrs(err.appendContextAttrs(nil)...)
 		r.AddAttrs(slog.Any(StackTraceKey, &stackValue{err: err}))
 	}
 	return r
@@ -0,0 +1,179 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Retryable is implemented by errors that know whether the operation that produced them may be retried.
+type Retryable interface {
+	Retryable() bool
+}
+
+// Temporary is implemented by errors that know whether they represent a temporary condition. Errors implementing this
+// interface are treated the same as those implementing Retryable.
+type Temporary interface {
+	Temporary() bool
+}
+
+type retryableError struct {
+	error
+	retryable bool
+}
+
+func (e *retryableError) Retryable() bool {
+	return e.retryable
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.error
+}
+
+// MarkRetryable returns an error that wraps err and marks it as retryable or not. Returns nil if err is nil.
+func MarkRetryable(err error, retryable bool) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{error: err, retryable: retryable}
+}
+
+// IsRetryable walks the chain of errors starting with err and returns the verdict of the first error that implements
+// either Retryable or Temporary. If no such error is found, the error is considered retryable if CodeOf(err) returns
+// CodeUnavailable or CodeTimeout.
+func IsRetryable(err error) bool {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		//nolint:errorlint // Explicitly only want to look at this exact error and not things wrapped inside it
+		switch marker := e.(type) {
+		case Retryable:
+			return marker.Retryable()
+		case Temporary:
+			return marker.Temporary()
+		case interface{ Unwrap() []error }:
+			for _, one := range marker.Unwrap() {
+				if IsRetryable(one) {
+					return true
+				}
+			}
+		case *Error:
+			if marker != nil && marker.next != nil {
+				for _, one := range marker.WrappedErrors() {
+					if IsRetryable(one) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	switch CodeOf(err) {
+	case CodeUnavailable, CodeTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// RetryPolicy provides configuration for Retry().
+type RetryPolicy struct {
+	// Classifier determines whether an error returned by an attempt may be retried. If nil, IsRetryable() is used.
+	Classifier func(err error) bool
+	// Sleep is used to wait between attempts. It should return early with an error if the context is done. If nil, an
+	// implementation equivalent to xtime.Sleep() is used (xtime cannot be used directly as it depends on this
+	// package).
+	Sleep func(ctx context.Context, d time.Duration) error
+	// InitialDelay is the delay before the second attempt. Zero or less means 100ms.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts. Zero or less means no cap.
+	MaxDelay time.Duration
+	// MaxElapsed is the maximum amount of time to spend retrying. No further attempts will be started if waiting for
+	// the next one would exceed this. Zero or less means no limit.
+	MaxElapsed time.Duration
+	// Multiplier is the factor the delay is multiplied by after each attempt. Values less than 1 mean 2.
+	Multiplier float64
+	// Jitter is the fraction, from 0 to 1, of each delay that may be randomly removed from it, to avoid many callers
+	// retrying in lockstep.
+	Jitter float64
+	// MaxAttempts is the maximum number of attempts to make, including the first. Zero or less means no limit.
+	MaxAttempts int
+}
+
+// Retry calls fn until it succeeds, returns an error that the policy's classifier deems not retryable, the policy's
+// limits are reached, or ctx is done. The delay between attempts grows exponentially. policy may be nil, in which case
+// only ctx and the classifier limit the number of attempts. On failure, the returned error contains one entry per
+// failed attempt, each with the error that attempt returned as its cause.
+func Retry(ctx context.Context, policy *RetryPolicy, fn func(ctx context.Context) error) error {
+	if policy == nil {
+		policy = &RetryPolicy{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	classifier := policy.Classifier
+	if classifier == nil {
+		classifier = IsRetryable
+	}
+	sleep := policy.Sleep
+	if sleep == nil {
+		sleep = sleepWithContext
+	}
+	delay := policy.InitialDelay
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	start := time.Now()
+	var result *Error
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		result = Append(result, NewWithCausef(err, "attempt %d failed", attempt))
+		if !classifier(err) || (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) {
+			return result
+		}
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+		wait := delay
+		if policy.Jitter > 0 {
+			//nolint:gosec // Jitter doesn't need to be cryptographically secure
+			wait -= time.Duration(float64(wait) * min(policy.Jitter, 1) * rand.Float64())
+		}
+		if policy.MaxElapsed > 0 && time.Since(start)+wait > policy.MaxElapsed {
+			return result
+		}
+		if err = sleep(ctx, wait); err != nil {
+			return Append(result, err)
+		}
+		delay = time.Duration(min(float64(delay)*multiplier, maxRetryDelay))
+	}
+}
+
+// maxRetryDelay is the largest delay that Retry() will compute, which prevents overflow.
+const maxRetryDelay = float64(math.MaxInt64 / 2)
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
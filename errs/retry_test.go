@@ -0,0 +1,132 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+type recordingSleeper struct {
+	delays []time.Duration
+}
+
+func (s *recordingSleeper) sleep(ctx context.Context, d time.Duration) error {
+	s.delays = append(s.delays, d)
+	return ctx.Err()
+}
+
+func TestIsRetryable(t *testing.T) {
+	c := check.New(t)
+	c.False(errs.IsRetryable(nil))
+	c.False(errs.IsRetryable(os.ErrNotExist))
+	c.True(errs.IsRetryable(errs.MarkRetryable(os.ErrNotExist, true)))
+	c.False(errs.IsRetryable(errs.MarkRetryable(os.ErrNotExist, false)))
+	c.True(errs.IsRetryable(fmt.Errorf("outer: %w", errs.MarkRetryable(os.ErrNotExist, true))))
+	c.True(errs.IsRetryable(errs.NewWithCause("outer", errs.MarkRetryable(os.ErrNotExist, true))))
+	c.True(errs.IsRetryable(errors.Join(os.ErrClosed, errs.MarkRetryable(os.ErrNotExist, true))))
+	c.True(errs.IsRetryable(errs.New("busy", errs.WithCode(errs.CodeUnavailable))))
+	c.True(errs.IsRetryable(errs.Append(errs.New("first"), errs.MarkRetryable(os.ErrNotExist, true))))
+	c.True(errs.IsRetryable(errs.Append(errs.New("first"), errs.New("busy", errs.WithCode(errs.CodeTimeout)))))
+	c.False(errs.IsRetryable(errs.Append(errs.New("first"), errs.New("second"))))
+	c.False(errs.IsRetryable(errs.MarkRetryable(errs.New("busy", errs.WithCode(errs.CodeUnavailable)), false)))
+	c.Nil(errs.MarkRetryable(nil, true))
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	c := check.New(t)
+	var sleeper recordingSleeper
+	attempts := 0
+	err := errs.Retry(context.Background(), &errs.RetryPolicy{
+		Sleep:        sleeper.sleep,
+		InitialDelay: time.Second,
+		MaxDelay:     3 * time.Second,
+	}, func(_ context.Context) error {
+		attempts++
+		if attempts < 4 {
+			return errs.MarkRetryable(errs.New("try again"), true)
+		}
+		return nil
+	})
+	c.NoError(err)
+	c.Equal(4, attempts)
+	c.Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, sleeper.delays)
+}
+
+func TestRetryRecordsEveryAttempt(t *testing.T) {
+	c := check.New(t)
+	var sleeper recordingSleeper
+	err := errs.Retry(context.Background(), &errs.RetryPolicy{
+		Sleep:       sleeper.sleep,
+		MaxAttempts: 3,
+		Jitter:      0.5,
+		Classifier:  func(error) bool { return true },
+	}, func(_ context.Context) error {
+		return os.ErrPermission
+	})
+	var e *errs.Error
+	c.True(errors.As(err, &e))
+	c.Equal(3, e.Count())
+	c.Equal("Multiple (3) errors occurred:\n- attempt 1 failed\n- attempt 2 failed\n- attempt 3 failed", e.Message())
+	c.True(errors.Is(e.WrappedErrors()[2], os.ErrPermission))
+	c.Equal(2, len(sleeper.delays))
+	c.True(sleeper.delays[0] > 50*time.Millisecond && sleeper.delays[0] <= 100*time.Millisecond)
+}
+
+func TestRetryStopsOnNonRetryable(t *testing.T) {
+	c := check.New(t)
+	attempts := 0
+	err := errs.Retry(context.Background(), nil, func(_ context.Context) error {
+		attempts++
+		return os.ErrNotExist
+	})
+	c.Equal(1, attempts)
+	c.True(errors.Is(err, os.ErrNotExist))
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	c := check.New(t)
+	var sleeper recordingSleeper
+	attempts := 0
+	err := errs.Retry(context.Background(), &errs.RetryPolicy{
+		Sleep:        sleeper.sleep,
+		InitialDelay: time.Hour,
+		MaxElapsed:   time.Minute,
+	}, func(_ context.Context) error {
+		attempts++
+		return errs.MarkRetryable(os.ErrClosed, true)
+	})
+	c.HasError(err)
+	c.Equal(1, attempts)
+	c.Equal(0, len(sleeper.delays))
+}
+
+func TestRetryHonorsContext(t *testing.T) {
+	c := check.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := errs.Retry(ctx, &errs.RetryPolicy{InitialDelay: time.Hour}, func(_ context.Context) error {
+		attempts++
+		cancel()
+		return errs.MarkRetryable(os.ErrClosed, true)
+	})
+	c.Equal(1, attempts)
+	var e *errs.Error
+	c.True(errors.As(err, &e))
+	c.Equal(2, e.Count())
+	c.True(errors.Is(e.WrappedErrors()[1], context.Canceled))
+}
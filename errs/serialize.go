@@ -0,0 +1,362 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+const binaryVersion = 1
+
+const (
+	binaryFlagWrapped = 1 << iota
+	binaryFlagCause
+	binaryFlagPlainCause
+)
+
+var (
+	_ json.Marshaler             = &Error{}
+	_ json.Unmarshaler           = &Error{}
+	_ encoding.BinaryMarshaler   = &Error{}
+	_ encoding.BinaryUnmarshaler = &Error{}
+)
+
+// serialError is the intermediate form used when serializing an Error. When Errors is non-empty, the node represents
+// an error that had multiple errors appended together and the remaining fields are unused. When Plain is true, the
+// node represents a cause that was not a detailed error and only Message is used.
+type serialError struct {
+	Message  string         `json:"message,omitempty"`
+	Code     string         `json:"code,omitempty"`
+	Category string         `json:"category,omitempty"`
+	Attrs    []serialAttr   `json:"attrs,omitempty"`
+	Stack    []string       `json:"stack,omitempty"`
+	Cause    *serialError   `json:"cause,omitempty"`
+	Errors   []*serialError `json:"errors,omitempty"`
+	Wrapped  bool           `json:"wrapped,omitempty"`
+	Plain    bool           `json:"plain,omitempty"`
+}
+
+type serialAttr struct {
+	Value any          `json:"value,omitempty"`
+	Key   string       `json:"key"`
+	Group []serialAttr `json:"group,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The message, code, category, attributes, symbolized stack trace, causes and
+// any appended errors are preserved. Attribute values are preserved only to the extent that they can survive a round
+// trip through JSON.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.toSerial())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Since the original program counters are not available, the rehydrated
+// error will return nil from RawStackTrace(), but will otherwise produce the same output from Detail() as the original.
+func (e *Error) UnmarshalJSON(in []byte) error {
+	var s serialError
+	if err := json.Unmarshal(in, &s); err != nil {
+		return err
+	}
+	*e = *s.toError()
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, providing a more compact form than MarshalJSON() does.
+func (e *Error) MarshalBinary() ([]byte, error) {
+	if e == nil {
+		return nil, New("unable to marshal nil error")
+	}
+	return e.toSerial().appendBinary([]byte{binaryVersion})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *Error) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return New("unsupported binary error format")
+	}
+	d := binaryDecoder{data: data[1:]}
+	s := d.node()
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) != 0 {
+		return New("unexpected trailing data in binary error")
+	}
+	*e = *s.toError()
+	return nil
+}
+
+func (e *Error) toSerial() *serialError {
+	if e.next == nil {
+		return e.toSerialEntry()
+	}
+	s := &serialError{}
+	for one := e; one != nil; one = one.next {
+		s.Errors = append(s.Errors, one.toSerialEntry())
+	}
+	return s
+}
+
+func (e *Error) toSerialEntry() *serialError {
+	s := &serialError{
+		Message:  e.message,
+		Code:     e.code,
+		Category: e.category,
+		Attrs:    toSerialAttrs(e.attrs),
+		Stack:    e.stackLines(),
+		Wrapped:  e.wrapped,
+	}
+	if e.cause != nil {
+		//nolint:errorlint // Explicitly only want to look at this exact error and not things wrapped inside it
+		if detailed, ok := e.cause.(*Error); ok {
+			s.Cause = detailed.toSerial()
+		} else {
+			s.Cause = &serialError{Message: e.cause.Error(), Plain: true}
+		}
+	}
+	return s
+}
+
+func toSerialAttrs(attrs []slog.Attr) []serialAttr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	result := make([]serialAttr, 0, len(attrs))
+	for _, attr := range attrs {
+		v := attr.Value.Resolve()
+		if v.Kind() == slog.KindGroup {
+			result = append(result, serialAttr{Key: attr.Key, Group: toSerialAttrs(v.Group())})
+		} else {
+			result = append(result, serialAttr{Key: attr.Key, Value: v.Any()})
+		}
+	}
+	return result
+}
+
+func fromSerialAttrs(attrs []serialAttr) []slog.Attr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	result := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr.Group != nil {
+			result = append(result, slog.Attr{Key: attr.Key, Value: slog.GroupValue(fromSerialAttrs(attr.Group)...)})
+		} else {
+			result = append(result, slog.Any(attr.Key, attr.Value))
+		}
+	}
+	return result
+}
+
+func (s *serialError) toError() *Error {
+	if len(s.Errors) == 0 {
+		return s.toEntry()
+	}
+	var root, last *Error
+	for _, one := range s.Errors {
+		if one == nil {
+			continue
+		}
+		e := one.toEntry()
+		if last == nil {
+			root = e
+		} else {
+			last.next = e
+		}
+		last = e
+	}
+	if root == nil {
+		return &Error{}
+	}
+	return root
+}
+
+func (s *serialError) toEntry() *Error {
+	e := &Error{
+		message:  s.Message,
+		code:     s.Code,
+		category: s.Category,
+		attrs:    fromSerialAttrs(s.Attrs),
+		frames:   s.Stack,
+		wrapped:  s.Wrapped,
+	}
+	if s.Cause != nil {
+		if s.Cause.Plain {
+			e.cause = errors.New(s.Cause.Message)
+		} else {
+			e.cause = s.Cause.toError()
+		}
+	}
+	return e
+}
+
+func (s *serialError) appendBinary(buffer []byte) ([]byte, error) {
+	entries := s.Errors
+	if len(entries) == 0 {
+		entries = []*serialError{s}
+	}
+	buffer = binary.AppendUvarint(buffer, uint64(len(entries)))
+	for _, one := range entries {
+		buffer = appendBinaryString(buffer, one.Message)
+		buffer = appendBinaryString(buffer, one.Code)
+		buffer = appendBinaryString(buffer, one.Category)
+		buffer = binary.AppendUvarint(buffer, uint64(len(one.Stack)))
+		for _, line := range one.Stack {
+			buffer = appendBinaryString(buffer, line)
+		}
+		if len(one.Attrs) == 0 {
+			buffer = appendBinaryString(buffer, "")
+		} else {
+			data, err := json.Marshal(one.Attrs)
+			if err != nil {
+				return nil, err
+			}
+			buffer = appendBinaryString(buffer, string(data))
+		}
+		var flags byte
+		if one.Wrapped {
+			flags |= binaryFlagWrapped
+		}
+		if one.Cause != nil {
+			flags |= binaryFlagCause
+			if one.Cause.Plain {
+				flags |= binaryFlagPlainCause
+			}
+		}
+		buffer = append(buffer, flags)
+		if one.Cause != nil {
+			if one.Cause.Plain {
+				buffer = appendBinaryString(buffer, one.Cause.Message)
+			} else {
+				var err error
+				if buffer, err = one.Cause.appendBinary(buffer); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return buffer, nil
+}
+
+func appendBinaryString(buffer []byte, s string) []byte {
+	return append(binary.AppendUvarint(buffer, uint64(len(s))), s...)
+}
+
+type binaryDecoder struct {
+	err  error
+	data []byte
+}
+
+func (d *binaryDecoder) node() *serialError {
+	count := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	// Each entry requires at least 6 bytes, so use that to reject counts that can't possibly be satisfied
+	if count == 0 || count > uint64(len(d.data))/6 {
+		d.fail()
+		return nil
+	}
+	entries := make([]*serialError, 0, count)
+	for range count {
+		one := &serialError{
+			Message:  d.string(),
+			Code:     d.string(),
+			Category: d.string(),
+		}
+		if lines := d.uvarint(); d.err == nil && lines != 0 {
+			if lines > uint64(len(d.data)) {
+				d.fail()
+				return nil
+			}
+			one.Stack = make([]string, 0, lines)
+			for range lines {
+				one.Stack = append(one.Stack, d.string())
+			}
+		}
+		if attrs := d.string(); d.err == nil && attrs != "" {
+			if err := json.Unmarshal([]byte(attrs), &one.Attrs); err != nil {
+				d.err = err
+			}
+		}
+		flags := d.byte()
+		if d.err != nil {
+			return nil
+		}
+		one.Wrapped = flags&binaryFlagWrapped != 0
+		if flags&binaryFlagCause != 0 {
+			if flags&binaryFlagPlainCause != 0 {
+				one.Cause = &serialError{Message: d.string(), Plain: true}
+			} else {
+				one.Cause = d.node()
+			}
+		}
+		if d.err != nil {
+			return nil
+		}
+		entries = append(entries, one)
+	}
+	if len(entries) == 1 {
+		return entries[0]
+	}
+	return &serialError{Errors: entries}
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binaryDecoder) string() string {
+	size := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if size > uint64(len(d.data)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.data[:size])
+	d.data = d.data[size:]
+	return s
+}
+
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) == 0 {
+		d.fail()
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *binaryDecoder) fail() {
+	if d.err == nil {
+		d.err = New("truncated or corrupt binary error data")
+	}
+}
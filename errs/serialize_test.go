@@ -0,0 +1,93 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func complexError() *errs.Error {
+	inner := errs.NewWithCause("inner", os.ErrNotExist, errs.WithCode(errs.CodeNotFound)).With("path", "/tmp/x")
+	first := errs.NewWithCause("first", inner, errs.WithCategory("client"))
+	second := errs.Wrap(errors.New("second"))
+	third := errs.New("third").WithAttrs(slog.Group("grp", slog.String("a", "b")))
+	return errs.Append(first, second, third)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	c := check.New(t)
+	original := complexError()
+	data, err := json.Marshal(original)
+	c.NoError(err)
+	var rehydrated errs.Error
+	c.NoError(json.Unmarshal(data, &rehydrated))
+	c.Equal(original.Detail(), rehydrated.Detail())
+	c.Equal(original.Message(), rehydrated.Message())
+	c.Equal(3, rehydrated.Count())
+	list := rehydrated.WrappedErrors()
+	c.Equal(errs.CodeNotFound, errs.CodeOf(errors.Unwrap(list[0])))
+	c.Equal("client", rehydrated.Category())
+	c.Equal(len(original.WrappedErrors()), len(rehydrated.WrappedErrors()))
+	c.Nil(rehydrated.RawStackTrace())
+
+	attrs := errs.AttrsOf(list[0])
+	c.Equal(1, len(attrs))
+	c.Equal("/tmp/x", attrs[0].Value.String())
+	attrs = errs.AttrsOf(list[2])
+	c.Equal(1, len(attrs))
+	c.Equal(slog.KindGroup, attrs[0].Value.Kind())
+
+	again, err := json.Marshal(&rehydrated)
+	c.NoError(err)
+	c.Equal(string(data), string(again))
+
+	var nilErr *errs.Error
+	data, err = json.Marshal(nilErr)
+	c.NoError(err)
+	c.Equal("null", string(data))
+	c.HasError(json.Unmarshal([]byte("[1]"), &rehydrated))
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	c := check.New(t)
+	original := complexError()
+	data, err := original.MarshalBinary()
+	c.NoError(err)
+	jsonData, err := json.Marshal(original)
+	c.NoError(err)
+	c.True(len(data) < len(jsonData))
+	var rehydrated errs.Error
+	c.NoError(rehydrated.UnmarshalBinary(data))
+	c.Equal(original.Detail(), rehydrated.Detail())
+	c.Equal(3, rehydrated.Count())
+	c.Equal("client", rehydrated.Category())
+
+	single := errs.New("single")
+	data, err = single.MarshalBinary()
+	c.NoError(err)
+	c.NoError(rehydrated.UnmarshalBinary(data))
+	c.Equal(single.Detail(), rehydrated.Detail())
+	c.Equal(1, rehydrated.Count())
+
+	c.HasError(rehydrated.UnmarshalBinary(nil))
+	c.HasError(rehydrated.UnmarshalBinary([]byte{99}))
+	c.HasError(rehydrated.UnmarshalBinary(data[:len(data)-1]))
+	c.HasError(rehydrated.UnmarshalBinary(append(data, 0)))
+	var nilErr *errs.Error
+	_, err = nilErr.MarshalBinary()
+	c.HasError(err)
+}
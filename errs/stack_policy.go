@@ -0,0 +1,100 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultStackPolicy is the policy used when rendering stack traces via Detail(), StackTrace(), Error(), and when
+// logging. The zero value renders every frame of the first error's stack trace, plus those of its causes.
+//
+// This variable is not used in a thread-safe manner, so any alterations should be done before any goroutines are
+// started.
+var DefaultStackPolicy StackPolicy
+
+// StackPolicy controls how stack traces are rendered.
+type StackPolicy struct {
+	// ExcludePrefixes holds function prefixes, such as "net/http.", whose frames should be omitted. This is applied in
+	// addition to the filtering done by xruntime.StackFuncPrefixesToFilter.
+	ExcludePrefixes []string
+	// MaxDepth is the maximum number of frames to render for each stack trace. Values less than 1 mean no limit.
+	MaxDepth int
+	// CollapseCommon causes the frames a stack trace has in common with the end of the stack trace that encloses it
+	// (the error it is the cause of, or the previous appended error) to be collapsed into a single "... N more" line.
+	CollapseCommon bool
+	// IncludeAppended causes the messages and stack traces of all errors accumulated via calls to .Append() to be
+	// rendered, rather than just those of the first error.
+	IncludeAppended bool
+}
+
+// apply returns the lines that remain after filtering out excluded frames.
+func (p *StackPolicy) apply(lines []string) []string {
+	if len(p.ExcludePrefixes) == 0 {
+		return lines
+	}
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		f := frameFunction(line)
+		if !slices.ContainsFunc(p.ExcludePrefixes, func(prefix string) bool { return strings.HasPrefix(f, prefix) }) {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// write the lines to the buffer, collapsing those in common with enclosing and truncating to the maximum depth, as
+// dictated by the policy.
+func (p *StackPolicy) write(buffer *strings.Builder, lines, enclosing []string) {
+	if len(lines) == 0 {
+		return
+	}
+	omitted := 0
+	if p.CollapseCommon {
+		omitted = commonSuffixLength(lines, enclosing)
+		if omitted == len(lines) {
+			omitted-- // Always show at least one frame
+		}
+		lines = lines[:len(lines)-omitted]
+	}
+	if p.MaxDepth > 0 && len(lines) > p.MaxDepth {
+		omitted += len(lines) - p.MaxDepth
+		lines = lines[:p.MaxDepth]
+	}
+	buffer.WriteString("    ")
+	buffer.WriteString(strings.Join(lines, "\n    "))
+	if omitted > 0 {
+		buffer.WriteString("\n    ... ")
+		buffer.WriteString(strconv.Itoa(omitted))
+		buffer.WriteString(" more")
+	}
+}
+
+func commonSuffixLength(a, b []string) int {
+	i := len(a) - 1
+	j := len(b) - 1
+	for i >= 0 && j >= 0 && a[i] == b[j] {
+		i--
+		j--
+	}
+	return len(a) - 1 - i
+}
+
+// frameFunction extracts the function name from a line produced by xruntime.PCsToStackTrace().
+func frameFunction(line string) string {
+	if strings.HasPrefix(line, "[") {
+		if i := strings.IndexByte(line, ']'); i > 0 {
+			return line[1:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package errs_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+func deepError(depth int, msg string) *errs.Error {
+	if depth == 0 {
+		return errs.New(msg)
+	}
+	return deepError(depth-1, msg)
+}
+
+func TestStackPolicyZeroValueMatchesDefault(t *testing.T) {
+	c := check.New(t)
+	err := errs.NewWithCause("outer", deepError(3, "inner"))
+	c.Equal(err.Detail(), err.DetailWithPolicy(errs.StackPolicy{}))
+	c.Equal(err.StackTrace(), err.StackTraceWithPolicy(errs.StackPolicy{}))
+}
+
+func TestStackPolicyMaxDepth(t *testing.T) {
+	c := check.New(t)
+	err := deepError(5, "deep")
+	full := strings.Split(err.StackTrace(), "\n")
+	c.True(len(full) > 3)
+	lines := strings.Split(err.StackTraceWithPolicy(errs.StackPolicy{MaxDepth: 2}), "\n")
+	c.Equal(3, len(lines))
+	c.Equal(full[0], lines[0])
+	c.Equal(full[1], lines[1])
+	c.Equal("    ... "+strconv.Itoa(len(full)-2)+" more", lines[2])
+}
+
+func TestStackPolicyExcludePrefixes(t *testing.T) {
+	c := check.New(t)
+	err := deepError(2, "deep")
+	c.Contains(err.StackTrace(), "errs_test.deepError")
+	trace := err.StackTraceWithPolicy(errs.StackPolicy{
+		ExcludePrefixes: []string{"github.com/richardwilkes/toolbox/v2/errs_test.deepError"},
+	})
+	c.NotContains(trace, "errs_test.deepError")
+	c.Contains(trace, "errs_test.TestStackPolicyExcludePrefixes")
+}
+
+func TestStackPolicyCollapseCommon(t *testing.T) {
+	c := check.New(t)
+	err := errs.NewWithCause("outer", deepError(3, "inner"))
+	policy := errs.StackPolicy{CollapseCommon: true}
+	detail := err.DetailWithPolicy(policy)
+	c.Contains(detail, " more")
+	c.True(len(detail) < len(err.Detail()))
+	_, causeDetail, found := strings.Cut(detail, "Caused by: ")
+	c.True(found)
+	c.Contains(causeDetail, "errs_test.deepError")
+	c.NotContains(causeDetail, "errs_test.TestStackPolicyCollapseCommon")
+}
+
+func TestStackPolicyIncludeAppended(t *testing.T) {
+	c := check.New(t)
+	var err *errs.Error
+	for i := range 3 {
+		err = errs.Append(err, deepError(i, "entry "+strconv.Itoa(i)))
+	}
+	c.NotContains(err.Detail(), "Also: ")
+	detail := err.DetailWithPolicy(errs.StackPolicy{IncludeAppended: true, CollapseCommon: true})
+	c.Equal(2, strings.Count(detail, "  Also: "))
+	c.Contains(detail, "  Also: entry 1\n")
+	c.Contains(detail, "  Also: entry 2\n")
+	c.Equal(2, strings.Count(detail, " more"))
+}
+
+func TestDefaultStackPolicy(t *testing.T) {
+	c := check.New(t)
+	saved := errs.DefaultStackPolicy
+	defer func() { errs.DefaultStackPolicy = saved }()
+	err := deepError(5, "deep")
+	errs.DefaultStackPolicy = errs.StackPolicy{MaxDepth: 1}
+	lines := strings.Split(err.StackTrace(), "\n")
+	c.Equal(2, len(lines))
+	c.HasPrefix(lines[1], "    ... ")
+	c.Equal(3, len(strings.Split(err.Detail(), "\n")))
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// OverflowPolicy determines what happens when a notification is sent to a target whose asynchronous queue is full.
+type OverflowPolicy int
+
+// Possible values for OverflowPolicy.
+const (
+	// OverflowBlock causes the sender to block until there is room in the queue.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest pending notification to make room for the new one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the new notification.
+	OverflowDropNewest
+	// OverflowCoalesce replaces a pending notification with the same name with the new one, if there is one, and
+	// otherwise discards the oldest pending notification to make room for the new one.
+	OverflowCoalesce
+)
+
+// AsyncConfig provides configuration for asynchronous delivery of notifications.
+type AsyncConfig struct {
+	// Depth controls the maximum number of notifications that may be pending delivery to each target. Zero or less
+	// means to use an unbounded queue.
+	Depth int
+	// Overflow determines what happens when a notification is sent to a target whose queue is already at Depth.
+	Overflow OverflowPolicy
+}
+
+// SetAsync switches this notifier to asynchronous delivery when config is not nil, or back to synchronous delivery
+// when it is nil. In asynchronous mode, each target gets its own queue and a goroutine that delivers the notifications
+// in that queue, so a slow target no longer stalls the caller or other targets. Notifications are still queued to
+// targets in priority order and each target receives them in the order they were sent, but deliveries to different
+// targets happen concurrently. BatchMode() calls to a BatchTarget are placed in the same queue as its notifications
+// and are never discarded. Notifications that were queued under a previous configuration are delivered before this
+// method returns.
+//
+// With OverflowBlock, a target that sends a notification to itself while its own queue is full will deadlock.
+func (n *Notifier) SetAsync(config *AsyncConfig) {
+	n.lock.Lock()
+	queues := n.queues
+	n.queues = nil
+	if config != nil {
+		cfg := *config
+		n.async = &cfg
+		n.queues = make(map[Target]*asyncQueue)
+	} else {
+		n.async = nil
+	}
+	n.lock.Unlock()
+	for _, q := range queues {
+		q.wait()
+	}
+}
+
+// Flush waits until all notifications that have been queued for asynchronous delivery, including any queued by the
+// targets while handling them, have been delivered. Returns immediately when the notifier is in synchronous mode. Must
+// not be called from within a target's HandleNotification() or BatchMode() methods.
+func (n *Notifier) Flush() {
+	for {
+		n.lock.RLock()
+		queues := make([]*asyncQueue, 0, len(n.queues))
+		for _, q := range n.queues {
+			queues = append(queues, q)
+		}
+		n.lock.RUnlock()
+		waited := false
+		for _, q := range queues {
+			if q.wait() {
+				waited = true
+			}
+		}
+		if !waited {
+			return
+		}
+	}
+}
+
+// queuesFor returns the asynchronous queues for the targets, creating them as needed, or nil if the notifier is in
+// synchronous mode. Targets that are no longer registered are skipped, since a queue created for them would never be
+// removed.
+func (n *Notifier) queuesFor(targets []Target) []*asyncQueue {
+	n.lock.RLock()
+	if n.async == nil {
+		n.lock.RUnlock()
+		return nil
+	}
+	queues := make([]*asyncQueue, 0, len(targets))
+	var missing bool
+	for _, target := range targets {
+		if q, ok := n.queues[target]; ok {
+			queues = append(queues, q)
+		} else {
+			missing = true
+			break
+		}
+	}
+	n.lock.RUnlock()
+	if !missing {
+		return queues
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.async == nil {
+		return nil
+	}
+	queues = queues[:0]
+	for _, target := range targets {
+		q, ok := n.queues[target]
+		if !ok {
+			if _, registered := n.nameMap[target]; !registered {
+				continue
+			}
+			q = newAsyncQueue(n, target, n.async)
+			n.queues[target] = q
+		}
+		queues = append(queues, q)
+	}
+	return queues
+}
+
+// removeQueue discards the asynchronous queue for the target, if any. Must be called with the lock held.
+func (n *Notifier) removeQueue(target Target) {
+	if q, ok := n.queues[target]; ok {
+		delete(n.queues, target)
+		q.discard()
+	}
+}
+
+type pendingKind byte
+
+const (
+	pendingNotification pendingKind = iota
+	pendingBatchStart
+	pendingBatchEnd
+)
+
+type pending struct {
+	ctx      context.Context
+	data     any
+	producer any
+	name     string
+	kind     pendingKind
+}
+
+type asyncQueue struct {
+	notifier  *Notifier
+	target    Target
+	cond      *sync.Cond
+	pending   []pending
+	lock      sync.Mutex
+	depth     int
+	overflow  OverflowPolicy
+	running   bool
+	discarded bool
+}
+
+func newAsyncQueue(n *Notifier, target Target, config *AsyncConfig) *asyncQueue {
+	q := &asyncQueue{
+		notifier: n,
+		target:   target,
+		depth:    config.Depth,
+		overflow: config.Overflow,
+	}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+func (q *asyncQueue) enqueue(p pending) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.discarded {
+		return
+	}
+	if p.kind == pendingNotification && q.depth > 0 {
+		for q.notificationCount() >= q.depth {
+			switch q.overflow {
+			case OverflowDropOldest:
+				q.removeOldestNotification()
+			case OverflowDropNewest:
+				return
+			case OverflowCoalesce:
+				for i := range q.pending {
+					if q.pending[i].kind == pendingNotification && q.pending[i].name == p.name {
+						q.pending[i] = p
+						return
+					}
+				}
+				q.removeOldestNotification()
+			default:
+				q.cond.Wait()
+				if q.discarded {
+					return
+				}
+			}
+		}
+	}
+	q.pending = append(q.pending, p)
+	if !q.running {
+		q.running = true
+		go q.deliver()
+	}
+}
+
+func (q *asyncQueue) notificationCount() int {
+	count := 0
+	for _, p := range q.pending {
+		if isNotification(p) {
+			count++
+		}
+	}
+	return count
+}
+
+func (q *asyncQueue) removeOldestNotification() {
+	if i := slices.IndexFunc(q.pending, isNotification); i != -1 {
+		q.pending = slices.Delete(q.pending, i, i+1)
+	}
+}
+
+func (q *asyncQueue) deliver() {
+	for {
+		q.lock.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.cond.Broadcast()
+			q.lock.Unlock()
+			return
+		}
+		p := q.pending[0]
+		q.pending[0] = pending{}
+		q.pending = q.pending[1:]
+		q.cond.Broadcast()
+		q.lock.Unlock()
+		switch p.kind {
+		case pendingBatchStart, pendingBatchEnd:
+			if target, ok := q.target.(BatchTarget); ok {
+				q.notifier.notifyBatchTarget(target, p.kind == pendingBatchStart)
+			}
+		default:
+			q.notifier.notifyTarget(p.ctx, q.target, p.name, p.data, p.producer)
+		}
+	}
+}
+
+func isNotification(p pending) bool {
+	return p.kind == pendingNotification
+}
+
+// wait blocks until the queue is idle. Returns true if it had to wait.
+func (q *asyncQueue) wait() bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	waited := false
+	for q.running {
+		waited = true
+		q.cond.Wait()
+	}
+	return waited
+}
+
+// discard drops any pending notifications that have not yet started delivery and rejects any further ones.
+func (q *asyncQueue) discard() {
+	q.lock.Lock()
+	q.discarded = true
+	q.pending = slices.DeleteFunc(q.pending, isNotification)
+	q.cond.Broadcast()
+	q.lock.Unlock()
+}
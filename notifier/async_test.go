@@ -0,0 +1,215 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+// gatedTarget blocks delivery of each notification until the gate channel is closed.
+type gatedTarget struct {
+	started chan string
+	gate    chan struct{}
+	mockTarget
+}
+
+func newGatedTarget() *gatedTarget {
+	return &gatedTarget{
+		started: make(chan string, 100),
+		gate:    make(chan struct{}),
+	}
+}
+
+func (gt *gatedTarget) HandleNotification(name string, data, producer any) {
+	gt.started <- name
+	<-gt.gate
+	gt.mockTarget.HandleNotification(name, data, producer)
+}
+
+func names(notifications []notification) []string {
+	result := make([]string, len(notifications))
+	for i, one := range notifications {
+		result[i] = one.name
+	}
+	return result
+}
+
+func TestAsyncDoesNotBlockProducer(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{})
+	slow := newGatedTarget()
+	fast := &mockTarget{}
+	n.Register(slow, 1, "test")
+	n.Register(fast, 0, "test")
+
+	n.Notify("test.a", nil)
+	n.Notify("test.b", nil)
+	c.Equal("test.a", <-slow.started)
+	c.Eventually(func() bool { return len(fast.getNotifications()) == 2 }, time.Second, time.Millisecond)
+	c.Equal(0, len(slow.getNotifications()))
+
+	close(slow.gate)
+	n.Flush()
+	c.Equal([]string{"test.a", "test.b"}, names(slow.getNotifications()))
+	c.Equal([]string{"test.a", "test.b"}, names(fast.getNotifications()))
+}
+
+func TestAsyncOverflowPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected []any
+		policy   notifier.OverflowPolicy
+	}{
+		{name: "drop oldest", policy: notifier.OverflowDropOldest, expected: []any{"a", nil, "x", 1, "x", 2}},
+		{name: "drop newest", policy: notifier.OverflowDropNewest, expected: []any{"a", nil, "b", nil, "x", 1}},
+		{name: "coalesce", policy: notifier.OverflowCoalesce, expected: []any{"a", nil, "b", nil, "x", 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := check.New(t)
+			n := notifier.New(testRecoveryHandler)
+			n.SetAsync(&notifier.AsyncConfig{Depth: 2, Overflow: tc.policy})
+			target := newGatedTarget()
+			n.Register(target, 0, "a", "b", "x")
+
+			n.Notify("a", nil)
+			c.Equal("a", <-target.started) // "a" is now in flight and no longer occupies the queue
+			n.Notify("b", nil)
+			n.NotifyWithData("x", 1, nil)
+			n.NotifyWithData("x", 2, nil)
+			close(target.gate)
+			n.Flush()
+
+			var delivered []any
+			for _, one := range target.getNotifications() {
+				delivered = append(delivered, one.name, one.data)
+			}
+			c.Equal(tc.expected, delivered)
+		})
+	}
+}
+
+func TestAsyncOverflowBlock(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{Depth: 1})
+	target := newGatedTarget()
+	n.Register(target, 0, "test")
+
+	n.Notify("test.a", nil)
+	<-target.started
+	n.Notify("test.b", nil)
+	sent := make(chan struct{})
+	go func() {
+		n.Notify("test.c", nil)
+		close(sent)
+	}()
+	c.Never(func() bool {
+		select {
+		case <-sent:
+			return true
+		default:
+			return false
+		}
+	}, 50*time.Millisecond, 5*time.Millisecond)
+	close(target.gate)
+	<-sent
+	n.Flush()
+	c.Equal([]string{"test.a", "test.b", "test.c"}, names(target.getNotifications()))
+}
+
+func TestAsyncBatchOrdering(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{Depth: 1, Overflow: notifier.OverflowDropNewest})
+	target := &mockBatchTarget{}
+	n.Register(target, 0, "test")
+
+	n.StartBatch()
+	n.Notify("test", nil)
+	n.EndBatch()
+	n.Flush()
+	c.Equal([]bool{true, false}, target.getBatchStarts())
+	c.Equal(1, len(target.getNotifications()))
+}
+
+func TestAsyncUnregisterDiscardsPending(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{})
+	target := newGatedTarget()
+	n.Register(target, 0, "test")
+
+	n.Notify("test.a", nil)
+	<-target.started
+	n.Notify("test.b", nil)
+	n.Unregister(target)
+	close(target.gate)
+	n.Flush()
+	c.Eventually(func() bool { return len(target.getNotifications()) == 1 }, time.Second, time.Millisecond)
+	c.Never(func() bool { return len(target.getNotifications()) != 1 }, 20*time.Millisecond, time.Millisecond)
+}
+
+func TestAsyncNoQueueAfterUnregister(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{})
+	target := &mockBatchTarget{}
+	n.Register(target, 0, "test")
+
+	// The batch end is not delivered to a target that was unregistered during the batch, as that would require creating
+	// a queue for it that would never be removed.
+	n.StartBatch()
+	n.Flush()
+	n.Unregister(target)
+	n.EndBatch()
+	n.Flush()
+	c.Equal([]bool{true}, target.getBatchStarts())
+}
+
+func TestAsyncPanicRecovery(t *testing.T) {
+	c := check.New(t)
+
+	recovered := make(chan error, 1)
+	n := notifier.New(func(err error) { recovered <- err })
+	n.SetAsync(&notifier.AsyncConfig{})
+	n.Register(&panicTarget{}, 0, "test")
+	n.Notify("test", nil)
+	n.Flush()
+	err, ok := check.Receive(c, recovered, time.Second)
+	c.True(ok)
+	c.HasError(err)
+}
+
+func TestSetAsyncBackToSync(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{})
+	target := newGatedTarget()
+	n.Register(target, 0, "test")
+	n.Notify("test.a", nil)
+	<-target.started
+	close(target.gate)
+	n.SetAsync(nil)
+	c.Equal(1, len(target.getNotifications()))
+	n.Notify("test.b", nil)
+	c.Equal(2, len(target.getNotifications()))
+	n.Flush()
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// CoalesceConfig provides configuration for coalescing the notifications sent during a batch.
+type CoalesceConfig struct {
+	// Reducer is called to merge the data of a notification with that of an earlier notification with the same name and
+	// producer sent during the same batch. The value it returns is used as the data for the single notification that
+	// will be delivered. If nil, the data from the most recent notification is used.
+	Reducer func(name string, previous, next any) any
+}
+
+type coalesceKey struct {
+	producer any
+	name     string
+}
+
+type coalesced struct {
+	ctx      context.Context
+	reducer  func(name string, previous, next any) any
+	producer any
+	name     string
+	data     []any
+}
+
+// SetCoalescing enables coalescing of notifications when config is not nil, or disables it when it is nil. While
+// coalescing is enabled, notifications sent between the outermost StartBatch() and EndBatch() calls are not delivered
+// immediately. Instead, those sharing the same name and producer are merged together and each resulting notification
+// is delivered once when the batch ends, in the order in which the first of them was sent, along with the context from
+// the last of them. Notifications whose producer is not comparable are never merged with each other.
+//
+// Changing the configuration does not affect notifications that are already being held.
+func (n *Notifier) SetCoalescing(config *CoalesceConfig) {
+	n.lock.Lock()
+	if config != nil {
+		cfg := *config
+		n.coalescing = &cfg
+	} else {
+		n.coalescing = nil
+	}
+	n.lock.Unlock()
+}
+
+// coalesce holds the notification for later delivery if coalescing is enabled and a batch is in progress. Returns true
+// if the notification was held.
+func (n *Notifier) coalesce(ctx context.Context, name string, data, producer any) bool {
+	// Most notifications are not held, so check under the read lock first to avoid serializing them.
+	n.lock.RLock()
+	holding := n.coalescing != nil && n.batchLevel != 0 && n.enabled
+	n.lock.RUnlock()
+	if !holding {
+		return false
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.coalescing == nil || n.batchLevel == 0 || !n.enabled {
+		return false
+	}
+	if producer == nil || reflect.ValueOf(producer).Comparable() {
+		key := coalesceKey{name: name, producer: producer}
+		if c, ok := n.held[key]; ok {
+			c.ctx = ctx
+			c.data = append(c.data, data)
+			return true
+		}
+		c := &coalesced{ctx: ctx, reducer: n.coalescing.Reducer, name: name, producer: producer, data: []any{data}}
+		if n.held == nil {
+			n.held = make(map[coalesceKey]*coalesced)
+		}
+		n.held[key] = c
+		n.heldOrder = append(n.heldOrder, c)
+		return true
+	}
+	n.heldOrder = append(n.heldOrder, &coalesced{ctx: ctx, name: name, producer: producer, data: []any{data}})
+	return true
+}
+
+// takeCoalesced removes and returns the notifications being held. Must be called with the lock held.
+func (n *Notifier) takeCoalesced() []*coalesced {
+	held := n.heldOrder
+	n.heldOrder = nil
+	n.held = nil
+	return held
+}
+
+func (n *Notifier) deliverCoalesced(held []*coalesced) {
+	for _, c := range held {
+		n.deliver(c.ctx, c.name, c.reduce(n.recoveryHandler), c.producer)
+	}
+}
+
+func (c *coalesced) reduce(recoveryHandler func(error)) any {
+	last := len(c.data) - 1
+	if c.reducer == nil || last == 0 {
+		return c.data[last]
+	}
+	result := c.data[0]
+	xos.SafeCall(func() {
+		for _, one := range c.data[1:] {
+			result = c.reducer(c.name, result, one)
+		}
+	}, recoveryHandler)
+	return result
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+func TestCoalesceDeduplicates(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetCoalescing(&notifier.CoalesceConfig{})
+	target := &mockBatchTarget{}
+	n.Register(target, 0, "doc")
+
+	n.StartBatch()
+	n.StartBatch()
+	for i := range 100 {
+		n.NotifyWithData("doc.changed", i, "a")
+		n.NotifyWithData("doc.saved", i, "a")
+		n.NotifyWithData("doc.changed", i, "b")
+	}
+	n.EndBatch()
+	c.Equal(0, len(target.getNotifications()))
+	n.EndBatch()
+
+	notifications := target.getNotifications()
+	c.Equal([]notification{
+		{name: "doc.changed", data: 99, producer: "a"},
+		{name: "doc.saved", data: 99, producer: "a"},
+		{name: "doc.changed", data: 99, producer: "b"},
+	}, notifications)
+	c.Equal([]bool{true, false}, target.getBatchStarts())
+
+	// Outside of a batch, notifications are delivered immediately
+	target.reset()
+	n.NotifyWithData("doc.changed", 1, "a")
+	n.NotifyWithData("doc.changed", 2, "a")
+	c.Equal(2, len(target.getNotifications()))
+}
+
+func TestCoalesceReducer(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetCoalescing(&notifier.CoalesceConfig{
+		Reducer: func(_ string, previous, next any) any {
+			p, _ := previous.([]int) //nolint:errcheck // A nil result is fine
+			v, _ := next.(int)       //nolint:errcheck // A zero result is fine
+			return append(p, v)
+		},
+	})
+	target := &mockTarget{}
+	n.Register(target, 0, "sum")
+
+	n.StartBatch()
+	n.NotifyWithData("sum", nil, nil)
+	n.NotifyWithData("sum", 1, nil)
+	n.NotifyWithData("sum", 2, nil)
+	n.NotifyWithData("sum", 3, nil)
+	n.EndBatch()
+	c.Equal([]notification{{name: "sum", data: []int{1, 2, 3}}}, target.getNotifications())
+}
+
+func TestCoalesceReducerPanic(t *testing.T) {
+	c := check.New(t)
+
+	var recovered []error
+	n := notifier.New(func(err error) { recovered = append(recovered, err) })
+	n.SetCoalescing(&notifier.CoalesceConfig{Reducer: func(_ string, _, _ any) any { panic("bad reducer") }})
+	target := &mockTarget{}
+	n.Register(target, 0, "x")
+
+	n.StartBatch()
+	n.NotifyWithData("x", 1, nil)
+	n.NotifyWithData("x", 2, nil)
+	n.EndBatch()
+	c.Equal(1, len(recovered))
+	c.Equal([]notification{{name: "x", data: 1}}, target.getNotifications())
+}
+
+func TestCoalesceUncomparableProducer(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetCoalescing(&notifier.CoalesceConfig{})
+	target := &mockTarget{}
+	n.Register(target, 0, "x")
+
+	n.StartBatch()
+	n.Notify("x", []int{1})
+	n.Notify("x", []int{1})
+	n.EndBatch()
+	c.Equal(2, len(target.getNotifications()))
+}
+
+func TestCoalesceDisabledOrReset(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetCoalescing(&notifier.CoalesceConfig{})
+	target := &mockTarget{}
+	n.Register(target, 0, "x")
+
+	n.StartBatch()
+	n.Notify("x", nil)
+	n.SetEnabled(false)
+	n.EndBatch()
+	c.Equal(0, len(target.getNotifications()))
+
+	n.SetEnabled(true)
+	n.StartBatch()
+	n.Notify("x", nil)
+	n.Reset()
+	n.Register(target, 0, "x")
+	n.EndBatch()
+	c.Equal(0, len(target.getNotifications()))
+
+	n.SetCoalescing(nil)
+	n.StartBatch()
+	n.Notify("x", nil)
+	n.Notify("x", nil)
+	c.Equal(2, len(target.getNotifications()))
+	n.EndBatch()
+}
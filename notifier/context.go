@@ -0,0 +1,27 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+import "context"
+
+// NotifyContext sends a notification to all interested targets, passing ctx along to those that implement
+// ContextTarget, so that they may observe its deadline, cancellation and request-scoped values. Targets are still
+// notified if ctx is done; it is up to each target to decide how to react to that. In all other respects, this behaves
+// the same as NotifyWithData(). A nil ctx is treated as context.Background().
+func (n *Notifier) NotifyContext(ctx context.Context, name string, data, producer any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if n.Enabled() {
+		if name = normalizeName(name); name != "" && !n.coalesce(ctx, name, data, producer) {
+			n.deliver(ctx, name, data, producer)
+		}
+	}
+}
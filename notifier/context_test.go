@@ -0,0 +1,104 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+type ctxKey struct{}
+
+type contextTarget struct {
+	values []any
+	mockTarget
+	lock sync.Mutex
+}
+
+func (ct *contextTarget) HandleNotificationContext(ctx context.Context, name string, data, producer any) {
+	ct.lock.Lock()
+	ct.values = append(ct.values, ctx.Value(ctxKey{}))
+	ct.lock.Unlock()
+	ct.HandleNotification(name, data, producer)
+}
+
+func (ct *contextTarget) getValues() []any {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	return append([]any(nil), ct.values...)
+}
+
+func TestNotifyContext(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	ctxTarget := &contextTarget{}
+	plain := &mockTarget{}
+	n.Register(ctxTarget, 0, "test")
+	n.Register(plain, 0, "test")
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-1")
+	n.NotifyContext(ctx, "test", "data", "producer")
+	n.NotifyWithData("test", "data", "producer")
+	n.NotifyContext(nil, "test", "data", "producer") //nolint:staticcheck // Testing nil handling
+	c.Equal([]any{"request-1", nil, nil}, ctxTarget.getValues())
+	c.Equal(3, len(ctxTarget.getNotifications()))
+	c.Equal(3, len(plain.getNotifications()))
+}
+
+func TestNotifyContextAsyncAndCoalesced(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	n.SetAsync(&notifier.AsyncConfig{})
+	n.SetCoalescing(&notifier.CoalesceConfig{})
+	target := &contextTarget{}
+	n.Register(target, 0, "test")
+
+	n.NotifyContext(context.WithValue(context.Background(), ctxKey{}, 1), "test", nil, nil)
+	n.StartBatch()
+	n.NotifyContext(context.WithValue(context.Background(), ctxKey{}, 2), "test", nil, nil)
+	n.NotifyContext(context.WithValue(context.Background(), ctxKey{}, 3), "test", nil, nil)
+	n.EndBatch()
+	n.Flush()
+	c.Equal([]any{1, 3}, target.getValues())
+}
+
+func TestNotifyContextDeadline(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	var deadline time.Time
+	var hasDeadline bool
+	n.Register(&contextFuncTarget{fn: func(ctx context.Context) { deadline, hasDeadline = ctx.Deadline() }}, 0, "test")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	n.NotifyContext(ctx, "test", nil, nil)
+	c.True(hasDeadline)
+	expected, _ := ctx.Deadline() //nolint:errcheck // We know it has one
+	c.Equal(expected, deadline)
+}
+
+type contextFuncTarget struct {
+	fn func(ctx context.Context)
+}
+
+func (t *contextFuncTarget) HandleNotification(_ string, _, _ any) {
+	t.fn(context.Background())
+}
+
+func (t *contextFuncTarget) HandleNotificationContext(ctx context.Context, _ string, _, _ any) {
+	t.fn(ctx)
+}
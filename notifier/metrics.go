@@ -0,0 +1,134 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+import (
+	"sync"
+	"time"
+)
+
+// DeliveryStats holds counters for the delivery of notifications.
+type DeliveryStats struct {
+	// Deliveries is the number of times a target's handler was called, including those that panicked.
+	Deliveries uint64
+	// Panics is the number of deliveries whose handler panicked and was caught by the recovery handler.
+	Panics uint64
+	// Latency is the cumulative time spent within the handlers.
+	Latency time.Duration
+}
+
+// AverageLatency returns the average time spent within a handler per delivery.
+func (s DeliveryStats) AverageLatency() time.Duration {
+	if s.Deliveries == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Deliveries)
+}
+
+func (s *DeliveryStats) add(latency time.Duration, panicked bool) {
+	s.Deliveries++
+	s.Latency += latency
+	if panicked {
+		s.Panics++
+	}
+}
+
+// Metrics holds a snapshot of the delivery counters collected by a Notifier.
+type Metrics struct {
+	// ByName holds the counters for each notification name that was delivered.
+	ByName map[string]DeliveryStats
+	// ByTarget holds the counters for each target that is currently registered and has had notifications delivered to
+	// it.
+	ByTarget map[Target]DeliveryStats
+}
+
+type metrics struct {
+	byName   map[string]*DeliveryStats
+	byTarget map[Target]*DeliveryStats
+	lock     sync.Mutex
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		byName:   make(map[string]*DeliveryStats),
+		byTarget: make(map[Target]*DeliveryStats),
+	}
+}
+
+// record adds a delivery to the counters. The target's counters are only updated if registered() still reports it as
+// registered, which is checked while holding the lock so that a delivery finishing after the target was unregistered
+// cannot bring back counters that forget() discarded.
+func (m *metrics) record(name string, target Target, latency time.Duration, panicked bool,
+	registered func(Target) bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stats, ok := m.byName[name]
+	if !ok {
+		stats = &DeliveryStats{}
+		m.byName[name] = stats
+	}
+	stats.add(latency, panicked)
+	if !registered(target) {
+		return
+	}
+	if stats, ok = m.byTarget[target]; !ok {
+		stats = &DeliveryStats{}
+		m.byTarget[target] = stats
+	}
+	stats.add(latency, panicked)
+}
+
+func (m *metrics) forget(target Target) {
+	m.lock.Lock()
+	delete(m.byTarget, target)
+	m.lock.Unlock()
+}
+
+// MetricsEnabled returns true if delivery metrics are being collected.
+func (n *Notifier) MetricsEnabled() bool {
+	return n.metrics.Load() != nil
+}
+
+// SetMetricsEnabled sets whether delivery metrics are collected. Metrics are disabled by default. Enabling them when
+// they were disabled starts with fresh counters, while disabling them discards the counters.
+func (n *Notifier) SetMetricsEnabled(enabled bool) {
+	if enabled {
+		n.metrics.CompareAndSwap(nil, newMetrics())
+	} else {
+		n.metrics.Store(nil)
+	}
+}
+
+// ResetMetrics sets all of the delivery counters back to zero.
+func (n *Notifier) ResetMetrics() {
+	if m := n.metrics.Load(); m != nil {
+		n.metrics.CompareAndSwap(m, newMetrics())
+	}
+}
+
+// Metrics returns a snapshot of the delivery counters. Counters for a target are discarded when it is unregistered.
+// Calls to BatchMode() are not counted.
+func (n *Notifier) Metrics() Metrics {
+	snapshot := Metrics{
+		ByName:   make(map[string]DeliveryStats),
+		ByTarget: make(map[Target]DeliveryStats),
+	}
+	if m := n.metrics.Load(); m != nil {
+		m.lock.Lock()
+		for k, v := range m.byName {
+			snapshot.ByName[k] = *v
+		}
+		for k, v := range m.byTarget {
+			snapshot.ByTarget[k] = *v
+		}
+		m.lock.Unlock()
+	}
+	return snapshot
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+type slowTarget struct {
+	delay time.Duration
+}
+
+func (st *slowTarget) HandleNotification(_ string, _, _ any) {
+	time.Sleep(st.delay)
+}
+
+func TestMetrics(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	fast := &mockTarget{}
+	slow := &slowTarget{delay: 5 * time.Millisecond}
+	broken := &panicTarget{}
+	n.Register(fast, 0, "doc")
+	n.Register(slow, 0, "doc.changed")
+	n.Register(broken, 0, "doc.saved")
+
+	n.Notify("doc.changed", nil)
+	c.False(n.MetricsEnabled())
+	c.Equal(0, len(n.Metrics().ByName))
+
+	n.SetMetricsEnabled(true)
+	c.True(n.MetricsEnabled())
+	n.Notify("doc.changed", nil)
+	n.Notify("doc.changed", nil)
+	n.Notify("doc.saved", nil)
+
+	m := n.Metrics()
+	changed := m.ByName["doc.changed"]
+	c.Equal(uint64(4), changed.Deliveries)
+	c.Equal(uint64(0), changed.Panics)
+	c.True(changed.Latency >= 10*time.Millisecond)
+	saved := m.ByName["doc.saved"]
+	c.Equal(uint64(2), saved.Deliveries)
+	c.Equal(uint64(1), saved.Panics)
+
+	c.Equal(uint64(3), m.ByTarget[fast].Deliveries)
+	slowStats := m.ByTarget[slow]
+	c.Equal(uint64(2), slowStats.Deliveries)
+	c.True(slowStats.AverageLatency() >= 5*time.Millisecond)
+	c.True(slowStats.AverageLatency() > m.ByTarget[fast].AverageLatency())
+	c.Equal(uint64(1), m.ByTarget[broken].Panics)
+	c.Equal(time.Duration(0), notifier.DeliveryStats{}.AverageLatency())
+
+	n.Unregister(slow)
+	_, exists := n.Metrics().ByTarget[slow]
+	c.False(exists)
+
+	n.ResetMetrics()
+	c.Equal(0, len(n.Metrics().ByName))
+	n.Notify("doc.saved", nil)
+	c.Equal(uint64(2), n.Metrics().ByName["doc.saved"].Deliveries)
+
+	n.SetMetricsEnabled(false)
+	c.Equal(0, len(n.Metrics().ByTarget))
+}
+
+func TestMetricsUnregisterDuringDelivery(t *testing.T) {
+	c := check.New(t)
+	n := notifier.New(testRecoveryHandler)
+	n.SetMetricsEnabled(true)
+	target := newGatedTarget()
+	n.Register(target, 0, "doc")
+
+	done := make(chan bool, 1)
+	go func() {
+		n.Notify("doc.changed", nil)
+		done <- true
+	}()
+	_, ok := check.Receive(c, target.started, time.Second)
+	c.True(ok)
+	n.Unregister(target)
+	close(target.gate)
+	_, ok = check.Receive(c, done, time.Second)
+	c.True(ok)
+
+	m := n.Metrics()
+	_, exists := m.ByTarget[target]
+	c.False(exists)
+	c.Equal(uint64(1), m.ByName["doc.changed"].Deliveries)
+}
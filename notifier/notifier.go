@@ -10,10 +10,13 @@
 package notifier
 
 import (
+	"context"
 	"maps"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/richardwilkes/toolbox/v2/xos"
 )
@@ -33,9 +36,19 @@ type BatchTarget interface {
 	BatchMode(start bool)
 }
 
+// ContextTarget defines the method a target of notifications must implement to receive the context passed to
+// NotifyContext(). Targets that implement it will have this method called rather than HandleNotification().
+type ContextTarget interface {
+	Target
+	// HandleNotificationContext is called to deliver a notification.
+	HandleNotificationContext(ctx context.Context, name string, data, producer any)
+}
+
 // Notifier tracks targets of notifications and provides methods for notifying them.
 type Notifier struct {
 	recoveryHandler func(error)
+	metrics         atomic.Pointer[metrics]
+	tracer          atomic.Pointer[Tracer]
 	lockedData
 	lock sync.RWMutex
 }
@@ -43,7 +56,13 @@ type Notifier struct {
 type lockedData struct {
 	batchTargets  map[BatchTarget]bool
 	productionMap map[string]map[Target]int
+	patterns      map[string][]string
 	nameMap       map[Target]map[string]bool
+	async         *AsyncConfig
+	queues        map[Target]*asyncQueue
+	coalescing    *CoalesceConfig
+	held          map[coalesceKey]*coalesced
+	heldOrder     []*coalesced
 	currentBatch  []BatchTarget
 	batchLevel    int
 	enabled       bool
@@ -56,6 +75,7 @@ func New(recoveryHandler func(error)) *Notifier {
 		lockedData: lockedData{
 			batchTargets:  make(map[BatchTarget]bool),
 			productionMap: make(map[string]map[Target]int),
+			patterns:      make(map[string][]string),
 			nameMap:       make(map[Target]map[string]bool),
 			enabled:       true,
 		},
@@ -66,6 +86,10 @@ func New(recoveryHandler func(error)) *Notifier {
 // delivered first. 'names' are the names the target wishes to consume. Names are hierarchical (separated by a .), so
 // specifying a name of "foo.bar" will consume not only a produced name of "foo.bar", but all sub-names, such as
 // "foo.bar.a", but not "foo.barn" or "foo.barn.a".
+//
+// A name segment of "*" matches any single segment and a name segment of "**" matches zero or more segments, so
+// "doc.*" consumes "doc.opened" and "doc.closed", while "doc.**.changed" consumes "doc.changed" and
+// "doc.page.1.changed". As with other names, the sub-names of anything matched are also consumed.
 func (n *Notifier) Register(target Target, priority int, names ...string) {
 	var normalizedNames []string
 	for _, name := range names {
@@ -88,6 +112,7 @@ func (n *Notifier) Register(target Target, priority int, names ...string) {
 			if !ok3 {
 				set = make(map[Target]int)
 				n.productionMap[name] = set
+				n.addPattern(name)
 			}
 			set[target] = priority
 			targetNames[name] = true
@@ -139,6 +164,7 @@ func (n *Notifier) RegisterFromNotifier(other *Notifier) {
 			maps.Copy(pm, v)
 		} else {
 			n.productionMap[k] = v
+			n.addPattern(k)
 		}
 	}
 	for k, v := range nameMap {
@@ -151,9 +177,11 @@ func (n *Notifier) RegisterFromNotifier(other *Notifier) {
 	n.lock.Unlock()
 }
 
-// Unregister a target.
+// Unregister a target. Any notifications queued for asynchronous delivery to the target that have not yet started
+// delivery are discarded.
 func (n *Notifier) Unregister(target Target) {
 	n.lock.Lock()
+	n.removeQueue(target)
 	if nameMap, exists := n.nameMap[target]; exists {
 		if batchTarget, ok := target.(BatchTarget); ok {
 			delete(n.batchTargets, batchTarget)
@@ -163,12 +191,25 @@ func (n *Notifier) Unregister(target Target) {
 				delete(set, target)
 				if len(set) == 0 {
 					delete(n.productionMap, name)
+					delete(n.patterns, name)
 				}
 			}
 		}
 		delete(n.nameMap, target)
 	}
 	n.lock.Unlock()
+	// This must happen after the target has been removed from the name map, as that is what prevents deliveries that
+	// are still in progress from recording new counters for it.
+	if m := n.metrics.Load(); m != nil {
+		m.forget(target)
+	}
+}
+
+func (n *Notifier) registered(target Target) bool {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	_, exists := n.nameMap[target]
+	return exists
 }
 
 // Enabled returns true if this notifier is currently enabled.
@@ -190,45 +231,84 @@ func (n *Notifier) Notify(name string, producer any) {
 	n.NotifyWithData(name, nil, producer)
 }
 
-// NotifyWithData sends a notification to all interested targets. This is a synchronous notification and will not return
-// until all interested targets handle the notification.
+// NotifyWithData sends a notification to all interested targets. It is equivalent to calling NotifyContext() with
+// context.Background(). Unless asynchronous delivery has been enabled via SetAsync(), this is a synchronous
+// notification and will not return until all interested targets handle the notification. If coalescing has been
+// enabled via SetCoalescing() and a batch is in progress, the notification is instead held until the batch ends.
 func (n *Notifier) NotifyWithData(name string, data, producer any) {
-	if n.Enabled() {
-		if name = normalizeName(name); name != "" {
-			targets := make(map[Target]int)
-			names := strings.Split(name, ".")
-			n.lock.RLock()
-			if n.enabled {
-				var buffer strings.Builder
-				for _, one := range names {
-					buffer.WriteString(one)
-					one = buffer.String()
-					if set, ok := n.productionMap[one]; ok {
-						maps.Copy(targets, set)
-					}
-					buffer.WriteByte('.')
-				}
+	n.NotifyContext(context.Background(), name, data, producer)
+}
+
+func (n *Notifier) deliver(ctx context.Context, name string, data, producer any) {
+	targets := make(map[Target]int)
+	names := strings.Split(name, ".")
+	n.lock.RLock()
+	if n.enabled {
+		var buffer strings.Builder
+		for _, one := range names {
+			buffer.WriteString(one)
+			one = buffer.String()
+			if set, ok := n.productionMap[one]; ok {
+				maps.Copy(targets, set)
 			}
-			n.lock.RUnlock()
-			if len(targets) > 0 {
-				list := make([]Target, 0, len(targets))
-				for k := range targets {
-					list = append(list, k)
-				}
-				sort.Slice(list, func(i, j int) bool {
-					return targets[list[i]] > targets[list[j]]
-				})
-				for _, target := range list {
-					n.notifyTarget(target, name, data, producer)
-				}
+			buffer.WriteByte('.')
+		}
+		for pattern, segments := range n.patterns {
+			if matchPattern(segments, names) {
+				maps.Copy(targets, n.productionMap[pattern])
+			}
+		}
+	}
+	n.lock.RUnlock()
+	if len(targets) > 0 {
+		list := make([]Target, 0, len(targets))
+		for k := range targets {
+			list = append(list, k)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return targets[list[i]] > targets[list[j]]
+		})
+		if queues := n.queuesFor(list); queues != nil {
+			for _, q := range queues {
+				q.enqueue(pending{ctx: ctx, name: name, data: data, producer: producer})
+			}
+		} else {
+			for _, target := range list {
+				n.notifyTarget(ctx, target, name, data, producer)
 			}
 		}
 	}
 }
 
-func (n *Notifier) notifyTarget(target Target, name string, data, producer any) {
+func (n *Notifier) notifyTarget(ctx context.Context, target Target, name string, data, producer any) {
+	m := n.metrics.Load()
+	tracer := n.tracer.Load()
+	if m == nil && tracer == nil {
+		n.callTarget(ctx, target, name, data, producer)
+		return
+	}
+	if tracer != nil {
+		ctx = (*tracer).StartDelivery(ctx, name, target)
+	}
+	start := time.Now()
+	completed := n.callTarget(ctx, target, name, data, producer)
+	latency := time.Since(start)
+	if m != nil {
+		m.record(name, target, latency, !completed, n.registered)
+	}
+	if tracer != nil {
+		(*tracer).EndDelivery(ctx, name, target, latency, !completed)
+	}
+}
+
+func (n *Notifier) callTarget(ctx context.Context, target Target, name string, data, producer any) (completed bool) {
 	defer xos.PanicRecovery(n.recoveryHandler)
-	target.HandleNotification(name, data, producer)
+	if ct, ok := target.(ContextTarget); ok {
+		ct.HandleNotificationContext(ctx, name, data, producer)
+	} else {
+		target.HandleNotification(name, data, producer)
+	}
+	return true
 }
 
 // BatchLevel returns the current batch level.
@@ -256,8 +336,29 @@ func (n *Notifier) StartBatch() {
 		targets = n.currentBatch
 	}
 	n.lock.Unlock()
+	n.notifyBatchTargets(targets, true)
+}
+
+func (n *Notifier) notifyBatchTargets(targets []BatchTarget, start bool) {
+	if len(targets) == 0 {
+		return
+	}
+	list := make([]Target, len(targets))
+	for i, target := range targets {
+		list[i] = target
+	}
+	if queues := n.queuesFor(list); queues != nil {
+		kind := pendingBatchEnd
+		if start {
+			kind = pendingBatchStart
+		}
+		for _, q := range queues {
+			q.enqueue(pending{kind: kind})
+		}
+		return
+	}
 	for _, target := range targets {
-		n.notifyBatchTarget(target, true)
+		n.notifyBatchTarget(target, start)
 	}
 }
 
@@ -267,9 +368,12 @@ func (n *Notifier) notifyBatchTarget(target BatchTarget, start bool) {
 }
 
 // EndBatch informs all BatchTargets that were present when StartBatch() was called that a batch of notifications just
-// finished. If batch level is still greater than zero after being decremented, then no notifications will be made.
+// finished. If batch level is still greater than zero after being decremented, then no notifications will be made. Any
+// notifications that were held for coalescing during the batch are delivered just prior to informing the BatchTargets,
+// unless the notifier has since been disabled, in which case they are discarded.
 func (n *Notifier) EndBatch() {
 	var targets []BatchTarget
+	var held []*coalesced
 	n.lock.Lock()
 	// Mirror StartBatch unconditionally (independent of the enabled state); the batchLevel > 0 guard simply prevents an
 	// unmatched EndBatch from driving the level negative. Gating this on enabled would strand a batch that began while
@@ -279,21 +383,28 @@ func (n *Notifier) EndBatch() {
 		if n.batchLevel == 0 {
 			targets = n.currentBatch
 			n.currentBatch = nil
+			held = n.takeCoalesced()
 		}
 	}
 	n.lock.Unlock()
-	for _, target := range targets {
-		n.notifyBatchTarget(target, false)
-	}
+	n.deliverCoalesced(held)
+	n.notifyBatchTargets(targets, false)
 }
 
-// Reset removes all targets.
+// Reset removes all targets and sets the delivery counters back to zero. Any notifications held for coalescing or
+// queued for asynchronous delivery that have not yet started delivery are discarded.
 func (n *Notifier) Reset() {
+	n.ResetMetrics()
 	n.lock.Lock()
+	for target := range n.queues {
+		n.removeQueue(target)
+	}
 	n.batchTargets = make(map[BatchTarget]bool)
 	n.productionMap = make(map[string]map[Target]int)
+	n.patterns = make(map[string][]string)
 	n.nameMap = make(map[Target]map[string]bool)
 	n.currentBatch = nil
+	n.takeCoalesced()
 	n.batchLevel = 0
 	n.lock.Unlock()
 }
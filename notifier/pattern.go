@@ -0,0 +1,57 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	anySegment     = "*"
+	anySegmentsRun = "**"
+)
+
+// addPattern records the normalized name as a pattern if it contains wildcard segments. Must be called with the lock
+// held.
+func (n *Notifier) addPattern(name string) {
+	segments := strings.Split(name, ".")
+	if slices.ContainsFunc(segments, func(segment string) bool {
+		return segment == anySegment || segment == anySegmentsRun
+	}) {
+		n.patterns[name] = segments
+	}
+}
+
+// matchPattern returns true if the pattern segments match the name segments, or any prefix of them.
+func matchPattern(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case anySegmentsRun:
+			for i := range len(name) + 1 {
+				if matchPattern(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case anySegment:
+			if len(name) == 0 {
+				return false
+			}
+		default:
+			if len(name) == 0 || pattern[0] != name[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return true
+}
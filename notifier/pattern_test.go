@@ -0,0 +1,104 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+func TestWildcardPatterns(t *testing.T) {
+	for _, tc := range []struct {
+		pattern  string
+		matches  []string
+		excludes []string
+	}{
+		{
+			pattern:  "doc.*",
+			matches:  []string{"doc.opened", "doc.closed", "doc.page.changed"},
+			excludes: []string{"doc", "docs.opened", "other.doc.opened"},
+		},
+		{
+			pattern:  "doc.**.changed",
+			matches:  []string{"doc.changed", "doc.page.changed", "doc.page.1.changed", "doc.changed.title"},
+			excludes: []string{"doc", "doc.page", "doc.page.changes", "other.changed"},
+		},
+		{
+			pattern:  "*.saved",
+			matches:  []string{"doc.saved", "prefs.saved.now"},
+			excludes: []string{"saved", "doc.page.saved"},
+		},
+		{
+			pattern:  "**",
+			matches:  []string{"a", "a.b.c"},
+			excludes: []string{""},
+		},
+		{
+			pattern:  "a.*.c.**",
+			matches:  []string{"a.b.c", "a.x.c.d.e"},
+			excludes: []string{"a.b", "a.b.d.c"},
+		},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			c := check.New(t)
+			n := notifier.New(testRecoveryHandler)
+			target := &mockTarget{}
+			n.Register(target, 0, tc.pattern)
+			for _, name := range tc.matches {
+				target.reset()
+				n.Notify(name, nil)
+				c.Equal(1, len(target.getNotifications()), name)
+			}
+			for _, name := range tc.excludes {
+				target.reset()
+				n.Notify(name, nil)
+				c.Equal(0, len(target.getNotifications()), name)
+			}
+		})
+	}
+}
+
+func TestWildcardPriorityAndDeduplication(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	var order []string
+	low := &orderTarget{name: "low", onNotify: func() { order = append(order, "low") }}
+	high := &orderTarget{name: "high", onNotify: func() { order = append(order, "high") }}
+	n.Register(low, 1, "doc.*", "doc.**.changed")
+	n.Register(high, 5, "doc")
+	n.Notify("doc.page.changed", nil)
+	c.Equal([]string{"high", "low"}, order)
+}
+
+func TestWildcardUnregisterAndCopy(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	target := &mockTarget{}
+	n.Register(target, 0, "doc.*")
+
+	other := notifier.New(testRecoveryHandler)
+	other.RegisterFromNotifier(n)
+	other.Notify("doc.opened", nil)
+	c.Equal(1, len(target.getNotifications()))
+
+	n.Unregister(target)
+	n.Notify("doc.opened", nil)
+	c.Equal(1, len(target.getNotifications()))
+	other.Notify("doc.closed", nil)
+	c.Equal(2, len(target.getNotifications()))
+
+	other.Reset()
+	other.Notify("doc.closed", nil)
+	c.Equal(2, len(target.getNotifications()))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+// Topic provides type-safe publishing and subscribing for notifications with a particular name. It is a thin layer over
+// a Notifier, so priorities, batching, asynchronous delivery and the recovery handler all behave just as they do for
+// notifications sent and received directly through the Notifier.
+type Topic[T any] struct {
+	notifier *Notifier
+	name     string
+}
+
+// Subscription is a handle to a subscription made via Topic.Subscribe() and friends.
+type Subscription struct {
+	notifier *Notifier
+	target   Target
+}
+
+// NewTopic creates a new Topic for the name on the notifier.
+func NewTopic[T any](n *Notifier, name string) *Topic[T] {
+	return &Topic[T]{notifier: n, name: normalizeName(name)}
+}
+
+// Name returns the normalized name of the topic.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish sends data to all subscribers of the topic, with a nil producer.
+func (t *Topic[T]) Publish(data T) {
+	t.notifier.NotifyWithData(t.name, data, nil)
+}
+
+// PublishFrom sends data to all subscribers of the topic, identifying producer as its source.
+func (t *Topic[T]) PublishFrom(data T, producer any) {
+	t.notifier.NotifyWithData(t.name, data, producer)
+}
+
+// Subscribe registers handler to receive the topic's notifications with a priority of zero. See Notifier.Register() for
+// details on how names are matched. Notifications for matching names whose data is not of type T are ignored, except
+// that nil data is delivered as the zero value of T.
+func (t *Topic[T]) Subscribe(handler func(data T, producer any)) Subscription {
+	return t.SubscribeWithPriority(0, handler)
+}
+
+// SubscribeWithPriority is the same as Subscribe(), but with a specific priority. Higher values are delivered first.
+func (t *Topic[T]) SubscribeWithPriority(priority int, handler func(data T, producer any)) Subscription {
+	target := &topicTarget[T]{handler: handler}
+	t.notifier.Register(target, priority, t.name)
+	return Subscription{notifier: t.notifier, target: target}
+}
+
+// SubscribeBatch is the same as SubscribeWithPriority(), but batchHandler is also called with true and false around a
+// batch of notifications started by Notifier.StartBatch() and ended by Notifier.EndBatch().
+func (t *Topic[T]) SubscribeBatch(priority int, handler func(data T, producer any),
+	batchHandler func(start bool)) Subscription {
+	target := &topicBatchTarget[T]{topicTarget: topicTarget[T]{handler: handler}, batchHandler: batchHandler}
+	t.notifier.Register(target, priority, t.name)
+	return Subscription{notifier: t.notifier, target: target}
+}
+
+// Cancel the subscription. Safe to call more than once, as well as on the zero value.
+func (s Subscription) Cancel() {
+	if s.notifier != nil {
+		s.notifier.Unregister(s.target)
+	}
+}
+
+type topicTarget[T any] struct {
+	handler func(data T, producer any)
+}
+
+func (t *topicTarget[T]) HandleNotification(_ string, data, producer any) {
+	if data == nil {
+		var zero T
+		t.handler(zero, producer)
+	} else if typed, ok := data.(T); ok {
+		t.handler(typed, producer)
+	}
+}
+
+type topicBatchTarget[T any] struct {
+	batchHandler func(start bool)
+	topicTarget[T]
+}
+
+func (t *topicBatchTarget[T]) BatchMode(start bool) {
+	t.batchHandler(start)
+}
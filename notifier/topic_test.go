@@ -0,0 +1,100 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+type docChange struct {
+	path  string
+	count int
+}
+
+func TestTopicPublishSubscribe(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	topic := notifier.NewTopic[docChange](n, ".doc..changed")
+	c.Equal("doc.changed", topic.Name())
+
+	var received []docChange
+	var producers []any
+	sub := topic.Subscribe(func(data docChange, producer any) {
+		received = append(received, data)
+		producers = append(producers, producer)
+	})
+	topic.Publish(docChange{path: "a", count: 1})
+	topic.PublishFrom(docChange{path: "b", count: 2}, "editor")
+	c.Equal([]docChange{{path: "a", count: 1}, {path: "b", count: 2}}, received)
+	c.Equal([]any{nil, "editor"}, producers)
+
+	// Data of the wrong type is ignored, while nil data becomes the zero value
+	n.NotifyWithData("doc.changed", "wrong", nil)
+	n.Notify("doc.changed", nil)
+	c.Equal([]docChange{{path: "a", count: 1}, {path: "b", count: 2}, {}}, received)
+
+	sub.Cancel()
+	sub.Cancel()
+	topic.Publish(docChange{path: "c"})
+	c.Equal(3, len(received))
+
+	var zero notifier.Subscription
+	zero.Cancel()
+}
+
+func TestTopicPriorityAndInterop(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	topic := notifier.NewTopic[int](n, "count")
+	var order []string
+	topic.SubscribeWithPriority(1, func(_ int, _ any) { order = append(order, "low") })
+	topic.SubscribeWithPriority(10, func(_ int, _ any) { order = append(order, "high") })
+	target := &mockTarget{}
+	n.Register(target, 5, "count")
+
+	topic.Publish(3)
+	c.Equal([]string{"high", "low"}, order)
+	notifications := target.getNotifications()
+	c.Equal(1, len(notifications))
+	c.Equal(3, notifications[0].data)
+}
+
+func TestTopicBatchAndRecovery(t *testing.T) {
+	c := check.New(t)
+
+	var recovered []error
+	n := notifier.New(func(err error) { recovered = append(recovered, err) })
+	topic := notifier.NewTopic[string](n, "msg")
+	var events []string
+	topic.SubscribeBatch(0, func(data string, _ any) {
+		events = append(events, data)
+		if data == "boom" {
+			panic(data)
+		}
+	}, func(start bool) {
+		if start {
+			events = append(events, "start")
+		} else {
+			events = append(events, "end")
+		}
+	})
+
+	n.StartBatch()
+	topic.Publish("one")
+	topic.Publish("boom")
+	n.EndBatch()
+	c.Equal([]string{"start", "one", "boom", "end"}, events)
+	c.Equal(1, len(recovered))
+}
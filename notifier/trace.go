@@ -0,0 +1,45 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer receives callbacks around each delivery of a notification to a target, allowing deliveries to be traced, for
+// example by starting and ending a span in a tracing system. The methods are called on the goroutine that makes the
+// delivery, which is not the goroutine that sent the notification when asynchronous delivery is enabled. Calls to
+// BatchMode() are not traced.
+type Tracer interface {
+	// StartDelivery is called just before the target's handler is called. The returned context is passed to the
+	// handler in place of ctx, so it may carry a span or other values for the handler to use.
+	StartDelivery(ctx context.Context, name string, target Target) context.Context
+	// EndDelivery is called just after the target's handler returns, with the context returned by StartDelivery(), the
+	// time spent within the handler, and whether the handler panicked.
+	EndDelivery(ctx context.Context, name string, target Target, latency time.Duration, panicked bool)
+}
+
+// Tracer returns the tracer set via SetTracer(), or nil if there isn't one.
+func (n *Notifier) Tracer() Tracer {
+	if t := n.tracer.Load(); t != nil {
+		return *t
+	}
+	return nil
+}
+
+// SetTracer sets the tracer that will be called around each delivery. Pass nil to stop tracing.
+func (n *Notifier) SetTracer(tracer Tracer) {
+	if tracer == nil {
+		n.tracer.Store(nil)
+	} else {
+		n.tracer.Store(&tracer)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package notifier_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/notifier"
+)
+
+type spanKey struct{}
+
+type traceRecord struct {
+	span     any
+	name     string
+	panicked bool
+}
+
+type recordingTracer struct {
+	records []traceRecord
+	started int
+	lock    sync.Mutex
+}
+
+func (rt *recordingTracer) StartDelivery(ctx context.Context, _ string, _ notifier.Target) context.Context {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	rt.started++
+	return context.WithValue(ctx, spanKey{}, rt.started)
+}
+
+func (rt *recordingTracer) EndDelivery(ctx context.Context, name string, _ notifier.Target, _ time.Duration,
+	panicked bool) {
+	rt.lock.Lock()
+	rt.records = append(rt.records, traceRecord{span: ctx.Value(spanKey{}), name: name, panicked: panicked})
+	rt.lock.Unlock()
+}
+
+func TestTracer(t *testing.T) {
+	c := check.New(t)
+
+	n := notifier.New(testRecoveryHandler)
+	c.Nil(n.Tracer())
+	tracer := &recordingTracer{}
+	n.SetTracer(tracer)
+	c.True(n.Tracer() == notifier.Tracer(tracer))
+
+	var seenSpan any
+	n.Register(&contextFuncTarget{fn: func(ctx context.Context) { seenSpan = ctx.Value(spanKey{}) }}, 1, "doc")
+	n.Register(&panicTarget{}, 0, "doc.saved")
+	n.Notify("doc.saved", nil)
+
+	// The handler sees the context returned by StartDelivery()
+	c.Equal(1, seenSpan)
+	c.Equal([]traceRecord{
+		{span: 1, name: "doc.saved"},
+		{span: 2, name: "doc.saved", panicked: true},
+	}, tracer.records)
+
+	n.SetTracer(nil)
+	c.Nil(n.Tracer())
+	n.Notify("doc", nil)
+	c.Equal(2, len(tracer.records))
+	c.Nil(seenSpan)
+}
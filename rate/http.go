@@ -0,0 +1,166 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/xhttp"
+)
+
+// HTTPConfig provides configuration for HTTPWrap().
+type HTTPConfig struct {
+	// Limiters provides the limiter for each client. Its parent limiter should have been created by one of New(),
+	// NewWithClock(), NewTokenBucket() or NewTokenBucketWithClock(), as requests using other Limiter implementations
+	// are always delayed until capacity is available rather than being rejected. Required.
+	Limiters *KeyedLimiter[string]
+	// Key returns the key that identifies the client making the request. If nil, the string form of the IP address
+	// returned by xhttp.ClientIP() is used.
+	Key func(req *http.Request) string
+	// Cost returns the amount of capacity the request consumes. If nil, each request costs 1.
+	Cost func(req *http.Request) int
+	// MaxDelay is the longest a request will be delayed while waiting for capacity before being rejected instead. Zero
+	// or less means requests are rejected as soon as the client has exceeded its limit. For limiters that use fixed
+	// time windows, a request that cannot be satisfied right away is rejected if the current window ends more than
+	// MaxDelay from now.
+	MaxDelay time.Duration
+}
+
+// HTTPWrap wraps the given handler, throttling requests per client. Requests that would have to wait longer than the
+// configured maximum delay, or whose context is done while they wait, are rejected with a 429 (Too Many Requests)
+// status and a Retry-After header. Requests whose client limiter is closed while they wait, such as when the client's
+// key is discarded by the KeyedLimiter, are rejected with a 503 (Service Unavailable) status and a Retry-After header.
+// Unless the client's limiter is some other Limiter implementation, RateLimit-Limit and RateLimit-Remaining headers are
+// also added to each response, along with a RateLimit-Reset header when a request is rejected for exceeding the
+// client's limit.
+func HTTPWrap(next http.Handler, config *HTTPConfig) http.Handler {
+	key := config.Key
+	if key == nil {
+		key = func(req *http.Request) string { return xhttp.ClientIP(req).String() }
+	}
+	cost := config.Cost
+	if cost == nil {
+		cost = func(_ *http.Request) int { return 1 }
+	}
+	limiters := config.Limiters
+	maxDelay := max(config.MaxDelay, 0)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var proceed bool
+		switch l := limiters.Get(key(req)).(type) {
+		case TokenBucket:
+			proceed = throttleTokenBucket(w, req, l, cost(req), maxDelay)
+		case *limiter:
+			proceed = throttleFixedWindow(w, req, l, cost(req), maxDelay)
+		default:
+			select {
+			case err := <-l.Use(cost(req)):
+				if err != nil {
+					reject(w, req, err, l.Closed(), periodOf(l))
+				} else {
+					proceed = true
+				}
+			case <-req.Context().Done():
+				reject(w, req, req.Context().Err(), false, periodOf(l))
+			}
+		}
+		if proceed {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// throttleTokenBucket waits for the bucket to have the amount of tokens available, returning true if the request
+// should proceed. If it returns false, a response has already been written.
+func throttleTokenBucket(w http.ResponseWriter, req *http.Request, bucket TokenBucket, amount int,
+	maxDelay time.Duration) bool {
+	r := bucket.Reserve(amount)
+	header := w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(bucket.Cap(true)))
+	if !r.OK() {
+		header.Set("RateLimit-Remaining", "0")
+		reject(w, req, r.Err(), bucket.Closed(), periodOf(bucket))
+		return false
+	}
+	if delay := r.Delay(); delay > maxDelay {
+		r.Cancel()
+		header.Set("RateLimit-Remaining", "0")
+		header.Set("RateLimit-Reset", retryAfter(delay))
+		reject(w, req, nil, false, delay)
+		return false
+	}
+	if err := r.wait(req.Context()); err != nil {
+		header.Set("RateLimit-Remaining", "0")
+		reject(w, req, err, bucket.Closed(), r.Delay())
+		return false
+	}
+	header.Set("RateLimit-Remaining", strconv.Itoa(bucket.Available()))
+	return true
+}
+
+// throttleFixedWindow waits for the limiter to have the amount of capacity available, returning true if the request
+// should proceed. If it returns false, a response has already been written.
+func throttleFixedWindow(w http.ResponseWriter, req *http.Request, l *limiter, amount int,
+	maxDelay time.Duration) bool {
+	header := w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(l.Cap(true)))
+	r, delay := l.useWithin(amount, maxDelay)
+	if r == nil {
+		header.Set("RateLimit-Remaining", "0")
+		header.Set("RateLimit-Reset", retryAfter(delay))
+		reject(w, req, nil, false, delay)
+		return false
+	}
+	err := l.wait(req.Context(), r, maxDelay)
+	remaining, reset := l.window()
+	if err != nil {
+		header.Set("RateLimit-Remaining", "0")
+		reject(w, req, err, l.Closed(), reset)
+		return false
+	}
+	header.Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	return true
+}
+
+// reject responds with a 503 (Service Unavailable) status if the limiter was closed, or a 429 (Too Many Requests)
+// status otherwise, along with a Retry-After header. Closed limiters are retried after a second, since the next request
+// will get a fresh limiter.
+func reject(w http.ResponseWriter, req *http.Request, err error, closed bool, delay time.Duration) {
+	if err != nil {
+		xhttp.RequestWarning(req, err)
+	}
+	status := http.StatusTooManyRequests
+	if closed {
+		status = http.StatusServiceUnavailable
+		delay = time.Second
+	}
+	w.Header().Set("Retry-After", retryAfter(delay))
+	xhttp.ErrorStatus(w, status)
+}
+
+// retryAfter returns the delay as a whole number of seconds, rounded up, with a minimum of one.
+func retryAfter(delay time.Duration) string {
+	return strconv.Itoa(max(int(math.Ceil(delay.Seconds())), 1))
+}
+
+// periodOf returns the period over which the limiter's capacity is replenished, or one second if that can't be
+// determined.
+func periodOf(l Limiter) time.Duration {
+	switch one := l.(type) {
+	case *limiter:
+		return one.controller.period
+	case *tokenBucket:
+		return one.tree.period
+	default:
+		return time.Second
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/rate"
+	"github.com/richardwilkes/toolbox/v2/xtime"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func serve(handler http.Handler, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHTTPWrapRejects(t *testing.T) {
+	c := check.New(t)
+	parent := rate.NewTokenBucket(1000, 1000, time.Minute)
+	defer parent.Close()
+	limiters := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 2})
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{Limiters: limiters})
+
+	rec := serve(handler, "10.0.0.1:1234")
+	c.Equal(http.StatusOK, rec.Code)
+	c.Equal("2", rec.Header().Get("RateLimit-Limit"))
+	c.Equal("1", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = serve(handler, "10.0.0.1:1235")
+	c.Equal(http.StatusOK, rec.Code)
+	c.Equal("0", rec.Header().Get("RateLimit-Remaining"))
+
+	rec = serve(handler, "10.0.0.1:1236")
+	c.Equal(http.StatusTooManyRequests, rec.Code)
+	c.Equal("30", rec.Header().Get("Retry-After"))
+	c.Equal("30", rec.Header().Get("RateLimit-Reset"))
+	c.Equal("0", rec.Header().Get("RateLimit-Remaining"))
+
+	// A different client has its own limit
+	rec = serve(handler, "10.0.0.2:1234")
+	c.Equal(http.StatusOK, rec.Code)
+}
+
+func TestHTTPWrapDelays(t *testing.T) {
+	c := check.New(t)
+	parent := rate.NewTokenBucket(1000, 1000, time.Second)
+	defer parent.Close()
+	limiters := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 100})
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{
+		Limiters: limiters,
+		Key:      func(req *http.Request) string { return req.Header.Get("X-API-Key") },
+		Cost:     func(_ *http.Request) int { return 60 },
+		MaxDelay: time.Second,
+	})
+	c.Equal(http.StatusOK, serve(handler, "").Code)
+	start := time.Now()
+	c.Equal(http.StatusOK, serve(handler, "").Code)
+	c.True(time.Since(start) >= 100*time.Millisecond)
+}
+
+func TestHTTPWrapCostTooLarge(t *testing.T) {
+	c := check.New(t)
+	parent := rate.NewTokenBucket(10, 0, time.Second)
+	defer parent.Close()
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{
+		Limiters: rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 10}),
+		Cost:     func(_ *http.Request) int { return 11 },
+	})
+	rec := serve(handler, "10.0.0.1:1")
+	c.Equal(http.StatusTooManyRequests, rec.Code)
+	c.Equal("1", rec.Header().Get("Retry-After"))
+}
+
+func TestHTTPWrapClosedWhileWaiting(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	parent := rate.NewTokenBucketWithClock(1000, 1000, time.Minute, clock)
+	defer parent.Close()
+	limiters := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 1})
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{Limiters: limiters, MaxDelay: time.Hour})
+	c.Equal(http.StatusOK, serve(handler, "10.0.0.1:1").Code)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() { done <- serve(handler, "10.0.0.1:1") }()
+	clock.WaitForWaiters(1)
+	limiters.Remove("10.0.0.1")
+	rec, ok := check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.Equal(http.StatusServiceUnavailable, rec.Code)
+	c.Equal("1", rec.Header().Get("Retry-After"))
+}
+
+func TestHTTPWrapDeadlineBeforeReady(t *testing.T) {
+	c := check.New(t)
+	parent := rate.NewTokenBucket(1000, 1000, time.Minute)
+	defer parent.Close()
+	limiters := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 1})
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{Limiters: limiters, MaxDelay: time.Hour})
+	c.Equal(http.StatusOK, serve(handler, "10.0.0.1:1").Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = "10.0.0.1:1"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	c.Equal(http.StatusTooManyRequests, rec.Code)
+	c.Equal("60", rec.Header().Get("Retry-After"))
+}
+
+func TestHTTPWrapFixedWindow(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	parent := rate.NewWithClock(100, time.Minute, clock)
+	defer parent.Close()
+	limiters := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 1})
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{Limiters: limiters, MaxDelay: 30 * time.Second})
+	rec := serve(handler, "10.0.0.1:1")
+	c.Equal(http.StatusOK, rec.Code)
+	c.Equal("1", rec.Header().Get("RateLimit-Limit"))
+	c.Equal("0", rec.Header().Get("RateLimit-Remaining"))
+
+	// The current window ends further away than the maximum delay
+	rec = serve(handler, "10.0.0.1:1")
+	c.Equal(http.StatusTooManyRequests, rec.Code)
+	c.Equal("60", rec.Header().Get("Retry-After"))
+	c.Equal("60", rec.Header().Get("RateLimit-Reset"))
+	c.Equal("0", rec.Header().Get("RateLimit-Remaining"))
+
+	// Once the end of the window is close enough, requests wait for it
+	clock.Advance(45 * time.Second)
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() { done <- serve(handler, "10.0.0.1:1") }()
+	clock.WaitForWaiters(2)
+	clock.Advance(15 * time.Second)
+	rec, ok := check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.Equal(http.StatusOK, rec.Code)
+
+	// Requests that can never be satisfied are rejected
+	handler = rate.HTTPWrap(okHandler(), &rate.HTTPConfig{
+		Limiters: limiters,
+		Cost:     func(_ *http.Request) int { return 2 },
+	})
+	rec = serve(handler, "10.0.0.2:1")
+	c.Equal(http.StatusTooManyRequests, rec.Code)
+	c.Equal("60", rec.Header().Get("Retry-After"))
+}
+
+func TestHTTPWrapFixedWindowClientDisconnects(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	parent := rate.NewWithClock(100, time.Minute, clock)
+	defer parent.Close()
+	limiters := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 1})
+	handler := rate.HTTPWrap(okHandler(), &rate.HTTPConfig{Limiters: limiters, MaxDelay: time.Hour})
+	c.Equal(http.StatusOK, serve(handler, "10.0.0.1:1").Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = "10.0.0.1:1"
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		done <- rec
+	}()
+	clock.WaitForWaiters(2)
+	cancel()
+	rec, ok := check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.Equal(http.StatusTooManyRequests, rec.Code)
+
+	// The abandoned request no longer holds a place in the queue, so the capacity of the next window goes to the next
+	// request
+	go func() { done <- serve(handler, "10.0.0.1:1") }()
+	clock.WaitForWaiters(2)
+	clock.Advance(time.Minute)
+	rec, ok = check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.Equal(http.StatusOK, rec.Code)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate
+
+import (
+	"context"
+	"io"
+)
+
+// Reader wraps an io.Reader, throttling the number of bytes read from it per time period with a Limiter. Bandwidth
+// caps may be nested by using limiters created via Limiter.New(), such as one per connection under a shared global
+// limiter.
+type Reader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter Limiter
+}
+
+// Writer wraps an io.Writer, throttling the number of bytes written to it per time period with a Limiter. Bandwidth
+// caps may be nested by using limiters created via Limiter.New(), such as one per connection under a shared global
+// limiter.
+type Writer struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter Limiter
+}
+
+// NewReader creates a new Reader. If the limiter is a TokenBucket, waiting for capacity will be abandoned when ctx is
+// done. A nil ctx is treated as context.Background().
+func NewReader(ctx context.Context, r io.Reader, limiter Limiter) *Reader {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Reader{ctx: ctx, r: r, limiter: limiter}
+}
+
+// Read implements io.Reader. At most one period's worth of capacity is read at a time, and the bytes read are then
+// accounted for with the limiter, waiting as needed, before returning.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return r.r.Read(p)
+	}
+	if n, err = r.r.Read(p[:min(len(p), maxChunk(r.limiter))]); n > 0 {
+		if waitErr := wait(r.ctx, r.limiter, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+	return n, err
+}
+
+// NewWriter creates a new Writer. If the limiter is a TokenBucket, waiting for capacity will be abandoned when ctx is
+// done. A nil ctx is treated as context.Background().
+func NewWriter(ctx context.Context, w io.Writer, limiter Limiter) *Writer {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Writer{ctx: ctx, w: w, limiter: limiter}
+}
+
+// Write implements io.Writer. Data is written in chunks of at most one period's worth of capacity, waiting for the
+// limiter to grant the capacity before writing each chunk.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := min(len(p), maxChunk(w.limiter))
+		if err = wait(w.ctx, w.limiter, chunk); err != nil {
+			return n, err
+		}
+		var written int
+		written, err = w.w.Write(p[:chunk])
+		n += written
+		if err != nil {
+			return n, err
+		}
+		p = p[chunk:]
+	}
+	return n, nil
+}
+
+func maxChunk(limiter Limiter) int {
+	if b, ok := limiter.(*tokenBucket); ok {
+		return max(b.maxAmount(), 1)
+	}
+	return max(limiter.Cap(true), 1)
+}
+
+func wait(ctx context.Context, limiter Limiter, amount int) error {
+	if b, ok := limiter.(TokenBucket); ok {
+		return b.Wait(ctx, amount)
+	}
+	return <-limiter.Use(amount)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/rate"
+)
+
+func TestReader(t *testing.T) {
+	c := check.New(t)
+	limiter := rate.NewTokenBucket(1000, 100, 100*time.Millisecond)
+	defer limiter.Close()
+	data := strings.Repeat("x", 300)
+	start := time.Now()
+	result, err := io.ReadAll(rate.NewReader(context.Background(), strings.NewReader(data), limiter))
+	c.NoError(err)
+	c.Equal(data, string(result))
+	// The first 100 bytes are available immediately, the remaining 200 take 20ms to accumulate
+	c.True(time.Since(start) >= 15*time.Millisecond)
+}
+
+func TestReaderNested(t *testing.T) {
+	c := check.New(t)
+	global := rate.New(100, 50*time.Millisecond)
+	defer global.Close()
+	conn := global.New(1000)
+	data := strings.Repeat("y", 250)
+	r := rate.NewReader(nil, strings.NewReader(data), conn) //nolint:staticcheck // Testing nil handling
+	buffer := make([]byte, 1000)
+	n, err := r.Read(buffer)
+	c.NoError(err)
+	c.Equal(100, n) // Limited by the parent's capacity
+	start := time.Now()
+	result, err := io.ReadAll(r)
+	c.NoError(err)
+	c.Equal(150, len(result))
+	c.True(time.Since(start) >= 40*time.Millisecond)
+	n, err = r.Read(nil)
+	c.Equal(0, n)
+	c.True(errors.Is(err, io.EOF))
+}
+
+func TestWriter(t *testing.T) {
+	c := check.New(t)
+	limiter := rate.NewTokenBucket(1000, 100, 100*time.Millisecond)
+	defer limiter.Close()
+	var buffer bytes.Buffer
+	w := rate.NewWriter(context.Background(), &buffer, limiter)
+	start := time.Now()
+	n, err := w.Write(bytes.Repeat([]byte("z"), 300))
+	c.NoError(err)
+	c.Equal(300, n)
+	c.Equal(300, buffer.Len())
+	c.True(time.Since(start) >= 15*time.Millisecond)
+}
+
+func TestWriterCanceled(t *testing.T) {
+	c := check.New(t)
+	limiter := rate.NewTokenBucket(1, 10, time.Hour)
+	defer limiter.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	var buffer bytes.Buffer
+	w := rate.NewWriter(ctx, &buffer, limiter)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	n, err := w.Write(bytes.Repeat([]byte("z"), 25))
+	c.True(errors.Is(err, context.Canceled))
+	c.Equal(10, n)
+	c.Equal(10, buffer.Len())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return len(p) / 2, errors.New("write failed")
+}
+
+func TestWriterError(t *testing.T) {
+	c := check.New(t)
+	limiter := rate.New(10, time.Hour)
+	defer limiter.Close()
+	n, err := rate.NewWriter(context.Background(), failingWriter{}, limiter).Write(make([]byte, 20))
+	c.HasError(err)
+	c.Equal(5, n)
+
+	limiter.Close()
+	n, err = rate.NewWriter(context.Background(), failingWriter{}, limiter).Write(make([]byte, 20))
+	c.HasError(err)
+	c.Contains(err.Error(), "Limiter is closed")
+	c.Equal(0, n)
+}
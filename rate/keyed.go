@@ -0,0 +1,151 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/xtime"
+)
+
+// KeyedLimiterConfig provides configuration for a KeyedLimiter.
+type KeyedLimiterConfig struct {
+	// Capacity is the capacity given to the limiter created for each key. A capacity less than 1 is treated as 1.
+	Capacity int
+	// IdleTTL is how long a key may go unused before its limiter is discarded. Zero or less means keys are never
+	// discarded for being idle.
+	IdleTTL time.Duration
+	// Clock is used to determine how long keys have been idle. If nil, xtime.RealClock is used.
+	Clock xtime.Clock
+	// MaxKeys is the maximum number of keys to track. When a new key would exceed this, the least recently used key's
+	// limiter is discarded to make room. Zero or less means no limit.
+	MaxKeys int
+}
+
+// KeyedLimiter lazily creates a limiter for each key, such as a client IP address, user ID or API token, all of which
+// are subordinate to a shared parent limiter. This allows each key to be throttled individually while the parent caps
+// the total.
+type KeyedLimiter[K comparable] struct {
+	parent  Limiter
+	entries map[K]*list.Element
+	lru     *list.List
+	config  KeyedLimiterConfig
+	lock    sync.Mutex
+	closed  bool
+}
+
+type keyedEntry[K comparable] struct {
+	lastUsed time.Time
+	limiter  Limiter
+	key      K
+}
+
+// NewKeyedLimiter creates a new KeyedLimiter whose per-key limiters are created via parent.New(). config may be nil, in
+// which case each key gets a capacity of 1 and keys are never discarded.
+func NewKeyedLimiter[K comparable](parent Limiter, config *KeyedLimiterConfig) *KeyedLimiter[K] {
+	if config == nil {
+		config = &KeyedLimiterConfig{}
+	}
+	kl := &KeyedLimiter[K]{
+		parent:  parent,
+		entries: make(map[K]*list.Element),
+		lru:     list.New(),
+		config:  *config,
+	}
+	if kl.config.Clock == nil {
+		kl.config.Clock = xtime.RealClock
+	}
+	return kl
+}
+
+// Get returns the limiter for the key, creating it if necessary. Note that the returned limiter will be closed if the
+// key is later discarded, so callers should generally call Get() each time they need the limiter rather than holding
+// onto it.
+func (kl *KeyedLimiter[K]) Get(key K) Limiter {
+	kl.lock.Lock()
+	defer kl.lock.Unlock()
+	now := kl.config.Clock.Now()
+	kl.evictIdle(now)
+	if elem, ok := kl.entries[key]; ok {
+		entry := elem.Value.(*keyedEntry[K]) //nolint:errcheck // Only *keyedEntry[K] values are stored
+		entry.lastUsed = now
+		kl.lru.MoveToFront(elem)
+		return entry.limiter
+	}
+	limiter := kl.parent.New(kl.config.Capacity)
+	if kl.closed {
+		limiter.Close()
+		return limiter
+	}
+	if kl.config.MaxKeys > 0 {
+		for kl.lru.Len() >= kl.config.MaxKeys {
+			kl.remove(kl.lru.Back())
+		}
+	}
+	kl.entries[key] = kl.lru.PushFront(&keyedEntry[K]{key: key, limiter: limiter, lastUsed: now})
+	return limiter
+}
+
+// Use is a convenience for calling Use() on the limiter for the key.
+func (kl *KeyedLimiter[K]) Use(key K, amount int) <-chan error {
+	return kl.Get(key).Use(amount)
+}
+
+// Len returns the number of keys currently being tracked.
+func (kl *KeyedLimiter[K]) Len() int {
+	kl.lock.Lock()
+	defer kl.lock.Unlock()
+	kl.evictIdle(kl.config.Clock.Now())
+	return kl.lru.Len()
+}
+
+// Remove discards the limiter for the key, if any.
+func (kl *KeyedLimiter[K]) Remove(key K) {
+	kl.lock.Lock()
+	if elem, ok := kl.entries[key]; ok {
+		kl.remove(elem)
+	}
+	kl.lock.Unlock()
+}
+
+// Close discards the limiters for all keys. The parent limiter is not closed. Once closed, Get() returns a closed
+// limiter.
+func (kl *KeyedLimiter[K]) Close() {
+	kl.lock.Lock()
+	kl.closed = true
+	for kl.lru.Len() > 0 {
+		kl.remove(kl.lru.Back())
+	}
+	kl.lock.Unlock()
+}
+
+// evictIdle discards the limiters for keys that have been idle for longer than the configured TTL. Must be called with
+// the lock held.
+func (kl *KeyedLimiter[K]) evictIdle(now time.Time) {
+	if kl.config.IdleTTL <= 0 {
+		return
+	}
+	for elem := kl.lru.Back(); elem != nil; elem = kl.lru.Back() {
+		entry := elem.Value.(*keyedEntry[K]) //nolint:errcheck // Only *keyedEntry[K] values are stored
+		if now.Sub(entry.lastUsed) < kl.config.IdleTTL {
+			break
+		}
+		kl.remove(elem)
+	}
+}
+
+// remove discards the entry. Must be called with the lock held.
+func (kl *KeyedLimiter[K]) remove(elem *list.Element) {
+	entry := kl.lru.Remove(elem).(*keyedEntry[K]) //nolint:errcheck // Only *keyedEntry[K] values are stored
+	delete(kl.entries, entry.key)
+	entry.limiter.Close()
+}
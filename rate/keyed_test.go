@@ -0,0 +1,97 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/rate"
+	"github.com/richardwilkes/toolbox/v2/xtime"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+	c := check.New(t)
+	parent := rate.New(100, time.Hour)
+	defer parent.Close()
+	kl := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{Capacity: 10})
+
+	a := kl.Get("a")
+	c.Equal(10, a.Cap(false))
+	c.True(a == kl.Get("a"))
+	c.True(a != kl.Get("b"))
+	c.Equal(2, kl.Len())
+
+	c.NoError(<-kl.Use("a", 10))
+	c.HasError(<-kl.Use("a", 11))
+	c.NoError(<-kl.Use("b", 10))
+
+	kl.Remove("a")
+	c.True(a.Closed())
+	c.Equal(1, kl.Len())
+	c.True(a != kl.Get("a"))
+
+	kl.Close()
+	c.Equal(0, kl.Len())
+	c.True(kl.Get("c").Closed())
+	c.HasError(<-kl.Use("c", 1))
+	c.Equal(0, kl.Len())
+	c.False(parent.Closed())
+}
+
+func TestKeyedLimiterMaxKeys(t *testing.T) {
+	c := check.New(t)
+	parent := rate.NewTokenBucket(100, 0, time.Hour)
+	defer parent.Close()
+	kl := rate.NewKeyedLimiter[int](parent, &rate.KeyedLimiterConfig{Capacity: 5, MaxKeys: 2})
+
+	one := kl.Get(1)
+	two := kl.Get(2)
+	kl.Get(1) // Makes 2 the least recently used
+	kl.Get(3)
+	c.Equal(2, kl.Len())
+	c.False(one.Closed())
+	c.True(two.Closed())
+	_, isBucket := kl.Get(4).(rate.TokenBucket)
+	c.True(isBucket)
+	c.True(one.Closed())
+}
+
+func TestKeyedLimiterIdleTTL(t *testing.T) {
+	c := check.New(t)
+	parent := rate.New(100, time.Hour)
+	defer parent.Close()
+	clock := xtime.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	kl := rate.NewKeyedLimiter[string](parent, &rate.KeyedLimiterConfig{IdleTTL: time.Minute, Clock: clock})
+
+	idle := kl.Get("idle")
+	c.Equal(1, idle.Cap(false))
+	clock.Advance(30 * time.Second)
+	active := kl.Get("active")
+	clock.Advance(30 * time.Second)
+	c.Equal(1, kl.Len())
+	c.True(idle.Closed())
+	c.False(active.Closed())
+	clock.Advance(30 * time.Second)
+	c.Equal(0, kl.Len())
+	c.True(active.Closed())
+}
+
+func TestKeyedLimiterNilConfig(t *testing.T) {
+	c := check.New(t)
+	parent := rate.New(100, time.Hour)
+	defer parent.Close()
+	kl := rate.NewKeyedLimiter[string](parent, nil)
+	for i := range 100 {
+		kl.Get(string(rune('a' + i)))
+	}
+	c.Equal(100, kl.Len())
+}
@@ -12,10 +12,13 @@
 package rate
 
 import (
+	"context"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xtime"
 )
 
 // Limiter provides a rate limiter.
@@ -56,11 +59,14 @@ type limiter struct {
 }
 
 type controller struct {
-	root    *limiter
-	ticker  *time.Ticker
-	done    chan bool
-	waiting []*request
-	lock    sync.RWMutex
+	periodEnd time.Time
+	clock     xtime.Clock
+	root      *limiter
+	ticker    xtime.Ticker
+	done      chan bool
+	waiting   []*request
+	period    time.Duration
+	lock      sync.RWMutex
 }
 
 type request struct {
@@ -72,9 +78,21 @@ type request struct {
 // New creates a new top-level rate limiter. 'capacity' is the number of units (bytes, for example) allowed to be used
 // in a particular time 'period'. A capacity less than 1 is treated as 1.
 func New(capacity int, period time.Duration) Limiter {
+	return NewWithClock(capacity, period, nil)
+}
+
+// NewWithClock is the same as New(), except that the clock is used to determine when each time period ends. A nil clock
+// is treated as xtime.RealClock.
+func NewWithClock(capacity int, period time.Duration, clock xtime.Clock) Limiter {
+	if clock == nil {
+		clock = xtime.RealClock
+	}
 	c := &controller{
-		ticker: time.NewTicker(period),
-		done:   make(chan bool, 1),
+		periodEnd: clock.Now().Add(period),
+		clock:     clock,
+		ticker:    clock.NewTicker(period),
+		done:      make(chan bool, 1),
+		period:    period,
 	}
 	l := &limiter{
 		controller: c,
@@ -84,8 +102,9 @@ func New(capacity int, period time.Duration) Limiter {
 	go func() {
 		for {
 			select {
-			case <-c.ticker.C:
+			case now := <-c.ticker.C():
 				c.lock.Lock()
+				c.periodEnd = now.Add(c.period)
 				c.root.reset()
 				remaining := make([]*request, 0, len(c.waiting))
 				for _, req := range c.waiting {
@@ -171,54 +190,120 @@ func (l *limiter) LastUsed() int {
 }
 
 func (l *limiter) Use(amount int) <-chan error {
-	done := make(chan error, 1)
+	req, _ := l.useWithin(amount, -1)
+	return req.done
+}
+
+// useWithin is like Use(), except that a request that cannot be fulfilled right away is only queued if the current
+// time period ends within maxDelay. A negative maxDelay means there is no limit. If the request is not queued, nil is
+// returned along with the time remaining in the current time period.
+func (l *limiter) useWithin(amount int, maxDelay time.Duration) (*request, time.Duration) {
+	req := &request{
+		limiter: l,
+		amount:  amount,
+		done:    make(chan error, 1),
+	}
 	if amount < 0 {
-		done <- errs.Newf("Amount (%d) must be positive", amount)
-		return done
+		req.done <- errs.Newf("Amount (%d) must be positive", amount)
+		return req, 0
 	}
 	if amount == 0 {
-		done <- nil
-		return done
+		req.done <- nil
+		return req, 0
 	}
 	l.controller.lock.Lock()
+	defer l.controller.lock.Unlock()
 	if l.closed {
-		l.controller.lock.Unlock()
-		done <- errs.New("Limiter is closed")
-		return done
+		req.done <- errs.New("Limiter is closed")
+		return req, 0
 	}
 	if capacity := l.cappedCapacity(); amount > capacity {
-		l.controller.lock.Unlock()
-		done <- errs.Newf("Amount (%d) is greater than capacity (%d)", amount, capacity)
-		return done
+		req.done <- errs.Newf("Amount (%d) is greater than capacity (%d)", amount, capacity)
+		return req, 0
 	}
 	// Preserve FIFO ordering by only taking the fast path when no requests are already waiting. Otherwise a steady
 	// stream of smaller requests could keep slipping through the fast path and consuming capacity ahead of an
 	// earlier, larger request that is stuck in the queue, delaying or starving it.
 	if len(l.controller.waiting) == 0 && l.tryConsume(amount) {
-		l.controller.lock.Unlock()
-		done <- nil
-		return done
+		req.done <- nil
+		return req, 0
 	}
-	l.controller.waiting = append(l.controller.waiting, &request{
-		limiter: l,
-		amount:  amount,
-		done:    done,
-	})
-	l.controller.lock.Unlock()
-	return done
+	if maxDelay >= 0 {
+		if delay := l.controller.untilPeriodEnd(); delay > maxDelay {
+			return nil, delay
+		}
+	}
+	l.controller.waiting = append(l.controller.waiting, req)
+	return req, 0
 }
 
-// tryConsume attempts to reserve amount against this limiter and all of its ancestors, reporting whether it succeeded.
-// On success, the amount is added to the used count of this limiter and each ancestor. The controller lock must be
-// held.
-func (l *limiter) tryConsume(amount int) bool {
+// wait waits up to maxDelay for the request returned by useWithin() to be fulfilled. If that time elapses or ctx is
+// done first, the request is removed from the queue and an error is returned.
+func (l *limiter) wait(ctx context.Context, req *request, maxDelay time.Duration) error {
+	select {
+	case err := <-req.done:
+		return err
+	default:
+	}
+	timer := l.controller.clock.NewTimer(maxDelay)
+	defer timer.Stop()
+	var err error
+	select {
+	case err = <-req.done:
+		return err
+	case <-timer.C():
+		err = errs.Newf("Waiting for %d would exceed the maximum delay", req.amount)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if l.controller.dequeue(req) {
+		return err
+	}
+	// The request was fulfilled before it could be removed from the queue, so use its result instead.
+	return <-req.done
+}
+
+// window returns the amount that could be used right now without waiting, taking the parent limiters into account,
+// along with the time remaining in the current time period.
+func (l *limiter) window() (remaining int, reset time.Duration) {
+	l.controller.lock.RLock()
+	defer l.controller.lock.RUnlock()
+	return l.available(), l.controller.untilPeriodEnd()
+}
+
+// untilPeriodEnd returns the time remaining in the current time period. The controller lock must be held.
+func (c *controller) untilPeriodEnd() time.Duration {
+	return max(c.periodEnd.Sub(c.clock.Now()), 0)
+}
+
+// dequeue removes the request from the queue, returning false if it was no longer waiting.
+func (c *controller) dequeue(req *request) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if i := slices.Index(c.waiting, req); i != -1 {
+		c.waiting = slices.Delete(c.waiting, i, i+1)
+		return true
+	}
+	return false
+}
+
+// available returns the amount that could be used right now without waiting, taking the parent limiters into account.
+// The controller lock must be held.
+func (l *limiter) available() int {
 	available := l.capacity - l.used
 	for p := l.parent; p != nil; p = p.parent {
 		if pa := p.capacity - p.used; pa < available {
 			available = pa
 		}
 	}
-	if available < amount {
+	return max(available, 0)
+}
+
+// tryConsume attempts to reserve amount against this limiter and all of its ancestors, reporting whether it succeeded.
+// On success, the amount is added to the used count of this limiter and each ancestor. The controller lock must be
+// held.
+func (l *limiter) tryConsume(amount int) bool {
+	if l.available() < amount {
 		return false
 	}
 	l.used += amount
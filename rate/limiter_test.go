@@ -16,6 +16,7 @@ import (
 
 	"github.com/richardwilkes/toolbox/v2/check"
 	"github.com/richardwilkes/toolbox/v2/rate"
+	"github.com/richardwilkes/toolbox/v2/xtime"
 )
 
 func TestNew(t *testing.T) {
@@ -469,3 +470,23 @@ func TestHierarchicalConstraints(t *testing.T) {
 
 	parent.Close()
 }
+
+func TestNewWithClock(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	rl := rate.NewWithClock(10, time.Minute, clock)
+	defer rl.Close()
+
+	c.NoError(<-rl.Use(10))
+	done := rl.Use(5)
+	select {
+	case <-done:
+		t.Fatal("request should be waiting for the next period")
+	default:
+	}
+	clock.Advance(time.Minute)
+	err, ok := check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.NoError(err)
+	c.Equal(10, rl.LastUsed())
+}
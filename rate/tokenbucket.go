@@ -0,0 +1,403 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xtime"
+)
+
+// TokenBucket is a Limiter that uses the token bucket algorithm rather than fixed time windows. Tokens are added to the
+// bucket continuously at a rate of its capacity per period, up to its burst size, and each use of the limiter removes
+// the requested amount of tokens. Requests that cannot be satisfied immediately are queued in the order they were made.
+type TokenBucket interface {
+	Limiter
+
+	// NewBucket returns a new token bucket that is subordinate to this one, meaning that any tokens it hands out are
+	// also taken from its parent. Capacities and bursts less than 1 are treated as 1 and the capacity, respectively. If
+	// this bucket is closed, it returns itself.
+	NewBucket(capacity, burst int) TokenBucket
+
+	// Burst returns the maximum number of tokens the bucket can hold, and therefore the largest amount that can be
+	// used at once.
+	Burst() int
+
+	// SetBurst sets the maximum number of tokens the bucket can hold. A burst less than 1 is treated as 1.
+	SetBurst(burst int)
+
+	// Available returns the number of tokens that could be used right now without waiting, taking the parent buckets
+	// into account.
+	Available() int
+
+	// TryUse takes the amount of tokens from the bucket and returns true if they are available right now. Otherwise,
+	// nothing is taken and false is returned.
+	TryUse(amount int) bool
+
+	// Reserve takes the amount of tokens from the bucket, even if this puts the bucket into debt, and returns a
+	// Reservation that reports how long the caller must wait before acting. Check Reservation.OK() before using it, as
+	// requests that can never be satisfied, such as those larger than the burst, produce a failed reservation.
+	Reserve(amount int) *Reservation
+
+	// Wait blocks until the amount of tokens are available, ctx is done, or the bucket is closed. If ctx has a deadline
+	// that will expire before the tokens become available, an error is returned immediately. Tokens are returned to the
+	// bucket if the wait does not succeed.
+	Wait(ctx context.Context, amount int) error
+}
+
+// Reservation holds the result of a call to TokenBucket.Reserve().
+type Reservation struct {
+	readyAt  time.Time
+	err      error
+	bucket   *tokenBucket
+	amount   int
+	canceled bool
+}
+
+type bucketTree struct {
+	clock  xtime.Clock
+	period time.Duration
+	lock   sync.Mutex
+}
+
+type tokenBucket struct {
+	updated     time.Time
+	periodStart time.Time
+	tree        *bucketTree
+	parent      *tokenBucket
+	done        chan struct{}
+	children    []*tokenBucket
+	tokens      float64
+	capacity    int
+	burst       int
+	used        int
+	last        int
+	closed      bool
+}
+
+// NewTokenBucket creates a new token bucket that refills at a rate of capacity tokens per period and holds at most
+// burst tokens. The bucket starts out full. A capacity less than 1 is treated as 1, a burst less than 1 is treated as
+// the capacity, and a period of zero or less is treated as one second.
+func NewTokenBucket(capacity, burst int, period time.Duration) TokenBucket {
+	return NewTokenBucketWithClock(capacity, burst, period, nil)
+}
+
+// NewTokenBucketWithClock is the same as NewTokenBucket(), except that the bucket and any subordinate buckets created
+// from it use the clock to measure the passage of time. A nil clock is treated as xtime.RealClock.
+func NewTokenBucketWithClock(capacity, burst int, period time.Duration, clock xtime.Clock) TokenBucket {
+	if period <= 0 {
+		period = time.Second
+	}
+	if clock == nil {
+		clock = xtime.RealClock
+	}
+	return newTokenBucket(&bucketTree{clock: clock, period: period}, nil, capacity, burst, clock.Now())
+}
+
+func newTokenBucket(tree *bucketTree, parent *tokenBucket, capacity, burst int, now time.Time) *tokenBucket {
+	capacity = max(capacity, 1)
+	if burst < 1 {
+		burst = capacity
+	}
+	return &tokenBucket{
+		updated:     now,
+		periodStart: now,
+		tree:        tree,
+		parent:      parent,
+		done:        make(chan struct{}),
+		tokens:      float64(burst),
+		capacity:    capacity,
+		burst:       burst,
+	}
+}
+
+func (b *tokenBucket) New(capacity int) Limiter {
+	return b.NewBucket(capacity, 0)
+}
+
+func (b *tokenBucket) NewBucket(capacity, burst int) TokenBucket {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	if b.closed {
+		return b
+	}
+	child := newTokenBucket(b.tree, b, capacity, burst, b.tree.clock.Now())
+	b.children = append(b.children, child)
+	return child
+}
+
+func (b *tokenBucket) Cap(applyParentCaps bool) int {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	if !applyParentCaps {
+		return b.capacity
+	}
+	capacity := b.capacity
+	for p := b.parent; p != nil; p = p.parent {
+		capacity = min(capacity, p.capacity)
+	}
+	return capacity
+}
+
+func (b *tokenBucket) SetCap(capacity int) {
+	b.tree.lock.Lock()
+	b.advance(b.tree.clock.Now())
+	b.capacity = max(capacity, 1)
+	b.tree.lock.Unlock()
+}
+
+func (b *tokenBucket) Burst() int {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	return b.burst
+}
+
+func (b *tokenBucket) SetBurst(burst int) {
+	b.tree.lock.Lock()
+	b.advance(b.tree.clock.Now())
+	b.burst = max(burst, 1)
+	b.tokens = min(b.tokens, float64(b.burst))
+	b.tree.lock.Unlock()
+}
+
+func (b *tokenBucket) Available() int {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	if b.closed {
+		return 0
+	}
+	now := b.tree.clock.Now()
+	available := b.burst
+	for one := b; one != nil; one = one.parent {
+		one.advance(now)
+		available = min(available, int(max(one.tokens, 0)))
+	}
+	return available
+}
+
+// maxAmount returns the largest amount that can be requested at once, taking the parent buckets into account.
+func (b *tokenBucket) maxAmount() int {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	amount := b.burst
+	for p := b.parent; p != nil; p = p.parent {
+		amount = min(amount, p.burst)
+	}
+	return amount
+}
+
+func (b *tokenBucket) LastUsed() int {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	b.advance(b.tree.clock.Now())
+	return b.last
+}
+
+func (b *tokenBucket) Use(amount int) <-chan error {
+	done := make(chan error, 1)
+	r := b.Reserve(amount)
+	if r.err != nil || !r.readyAt.After(b.tree.clock.Now()) {
+		done <- r.err
+		return done
+	}
+	go func() { done <- r.wait(context.Background()) }()
+	return done
+}
+
+func (b *tokenBucket) TryUse(amount int) bool {
+	if amount <= 0 {
+		return amount == 0
+	}
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	if b.closed {
+		return false
+	}
+	now := b.tree.clock.Now()
+	for one := b; one != nil; one = one.parent {
+		one.advance(now)
+		if one.tokens < float64(amount) {
+			return false
+		}
+	}
+	b.take(amount)
+	return true
+}
+
+func (b *tokenBucket) Reserve(amount int) *Reservation {
+	r := &Reservation{bucket: b, amount: amount}
+	if amount < 0 {
+		r.err = errs.Newf("Amount (%d) must be positive", amount)
+		return r
+	}
+	now := b.tree.clock.Now()
+	r.readyAt = now
+	if amount == 0 {
+		return r
+	}
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	if b.closed {
+		r.err = errs.New("Limiter is closed")
+		return r
+	}
+	var wait time.Duration
+	for one := b; one != nil; one = one.parent {
+		if amount > one.burst {
+			r.err = errs.Newf("Amount (%d) is greater than burst (%d)", amount, one.burst)
+			return r
+		}
+		one.advance(now)
+		if deficit := float64(amount) - one.tokens; deficit > 0 {
+			wait = max(wait, time.Duration(deficit*float64(b.tree.period)/float64(one.capacity)))
+		}
+	}
+	b.take(amount)
+	r.readyAt = now.Add(wait)
+	return r
+}
+
+func (b *tokenBucket) Wait(ctx context.Context, amount int) error {
+	r := b.Reserve(amount)
+	if r.err != nil {
+		return r.err
+	}
+	return r.wait(ctx)
+}
+
+func (b *tokenBucket) Closed() bool {
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	return b.closed
+}
+
+func (b *tokenBucket) Close() {
+	b.tree.lock.Lock()
+	if !b.closed {
+		b.close()
+		if b.parent != nil {
+			for i, child := range b.parent.children {
+				if child == b {
+					j := len(b.parent.children) - 1
+					b.parent.children[i] = b.parent.children[j]
+					b.parent.children[j] = nil
+					b.parent.children = b.parent.children[:j]
+					break
+				}
+			}
+		}
+	}
+	b.tree.lock.Unlock()
+}
+
+func (b *tokenBucket) close() {
+	b.closed = true
+	close(b.done)
+	for _, child := range b.children {
+		child.close()
+	}
+	b.children = nil
+}
+
+// advance brings the token count and usage tracking up to date. Must be called with the tree lock held.
+func (b *tokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(b.tokens+float64(elapsed)*float64(b.capacity)/float64(b.tree.period), float64(b.burst))
+		b.updated = now
+	}
+	if periods := now.Sub(b.periodStart) / b.tree.period; periods > 0 {
+		if periods == 1 {
+			b.last = b.used
+		} else {
+			b.last = 0
+		}
+		b.used = 0
+		b.periodStart = b.periodStart.Add(periods * b.tree.period)
+	}
+}
+
+// take removes the amount of tokens from this bucket and its parents. Must be called with the tree lock held, after
+// having called advance() on each of them.
+func (b *tokenBucket) take(amount int) {
+	for one := b; one != nil; one = one.parent {
+		one.tokens -= float64(amount)
+		one.used += amount
+	}
+}
+
+// OK returns true if the reservation can be honored.
+func (r *Reservation) OK() bool {
+	return r.err == nil
+}
+
+// Err returns the reason the reservation cannot be honored, or nil if it can.
+func (r *Reservation) Err() error {
+	return r.err
+}
+
+// ReadyAt returns the time at which the reserved tokens become available.
+func (r *Reservation) ReadyAt() time.Time {
+	return r.readyAt
+}
+
+// Delay returns how long the caller must wait from now before the reserved tokens become available. Returns zero if
+// they are already available or the reservation cannot be honored.
+func (r *Reservation) Delay() time.Duration {
+	if r.err != nil {
+		return 0
+	}
+	return max(r.readyAt.Sub(r.bucket.tree.clock.Now()), 0)
+}
+
+// Cancel the reservation, returning its tokens to the bucket if they have not yet become available. Has no effect if
+// the reservation could not be honored or was already canceled.
+func (r *Reservation) Cancel() {
+	if r.err != nil || r.amount == 0 {
+		return
+	}
+	b := r.bucket
+	b.tree.lock.Lock()
+	defer b.tree.lock.Unlock()
+	now := b.tree.clock.Now()
+	if r.canceled || b.closed || !r.readyAt.After(now) {
+		return
+	}
+	r.canceled = true
+	for one := b; one != nil; one = one.parent {
+		one.advance(now)
+		one.tokens = min(one.tokens+float64(r.amount), float64(one.burst))
+		one.used = max(one.used-r.amount, 0)
+	}
+}
+
+func (r *Reservation) wait(ctx context.Context) error {
+	delay := r.Delay()
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		r.Cancel()
+		return errs.Newf("Waiting for %d would exceed the context deadline", r.amount)
+	}
+	timer := r.bucket.tree.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	case <-r.bucket.done:
+		return errs.New("Limiter is closed")
+	}
+}
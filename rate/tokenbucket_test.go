@@ -0,0 +1,230 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/rate"
+	"github.com/richardwilkes/toolbox/v2/xtime"
+)
+
+func TestNewTokenBucket(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(100, 0, time.Second)
+	c.Equal(100, tb.Cap(false))
+	c.Equal(100, tb.Burst())
+	c.Equal(0, tb.LastUsed())
+	c.False(tb.Closed())
+
+	tb.SetCap(0)
+	c.Equal(1, tb.Cap(false))
+	tb.SetBurst(10)
+	c.Equal(10, tb.Burst())
+	tb.Close()
+	c.True(tb.Closed())
+	tb.Close()
+
+	var limiter rate.Limiter = rate.NewTokenBucket(5, 10, 0)
+	c.Equal(5, limiter.Cap(true))
+	limiter.Close()
+}
+
+func TestTokenBucketTryUse(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(10, 5, time.Hour)
+	c.True(tb.TryUse(0))
+	c.False(tb.TryUse(-1))
+	c.True(tb.TryUse(3))
+	c.True(tb.TryUse(2))
+	c.False(tb.TryUse(1))
+	c.False(tb.TryUse(6))
+	tb.Close()
+	c.False(tb.TryUse(0) && tb.TryUse(1))
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(100, 10, 100*time.Millisecond)
+	defer tb.Close()
+	c.True(tb.TryUse(10))
+	c.False(tb.TryUse(1))
+	c.Eventually(func() bool { return tb.TryUse(5) }, time.Second, time.Millisecond)
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(10, 10, time.Second)
+	defer tb.Close()
+
+	r := tb.Reserve(10)
+	c.True(r.OK())
+	c.Equal(time.Duration(0), r.Delay())
+
+	r = tb.Reserve(5)
+	c.True(r.OK())
+	c.NoError(r.Err())
+	delay := r.Delay()
+	c.True(delay > 400*time.Millisecond && delay <= 500*time.Millisecond, delay)
+
+	// A later reservation queues up behind the earlier one
+	r2 := tb.Reserve(5)
+	c.True(r2.ReadyAt().After(r.ReadyAt()))
+
+	// Canceling returns the tokens, so a new reservation doesn't have to wait as long
+	r2.Cancel()
+	r.Cancel()
+	r.Cancel()
+	r3 := tb.Reserve(5)
+	c.True(r3.Delay() <= 500*time.Millisecond)
+
+	r = tb.Reserve(11)
+	c.False(r.OK())
+	c.Contains(r.Err().Error(), "Amount (11) is greater than burst (10)")
+	c.Equal(time.Duration(0), r.Delay())
+	r.Cancel()
+
+	r = tb.Reserve(-1)
+	c.False(r.OK())
+	c.Contains(r.Err().Error(), "Amount (-1) must be positive")
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(100, 10, 100*time.Millisecond)
+	defer tb.Close()
+
+	c.NoError(tb.Wait(context.Background(), 10))
+	start := time.Now()
+	c.NoError(tb.Wait(context.Background(), 5))
+	c.True(time.Since(start) >= 4*time.Millisecond)
+
+	// A deadline that can't be met fails immediately and returns the tokens
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := tb.Wait(ctx, 10)
+	c.HasError(err)
+	c.Contains(err.Error(), "would exceed the context deadline")
+
+	// Cancellation stops the wait
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel2()
+	}()
+	tb.SetCap(1)
+	err = tb.Wait(ctx2, 10)
+	c.True(errors.Is(err, context.Canceled))
+}
+
+func TestTokenBucketUse(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(100, 0, 100*time.Millisecond)
+	c.NoError(<-tb.Use(0))
+	c.NoError(<-tb.Use(100))
+	start := time.Now()
+	c.NoError(<-tb.Use(50))
+	c.True(time.Since(start) >= 40*time.Millisecond)
+
+	err := <-tb.Use(101)
+	c.HasError(err)
+	c.Contains(err.Error(), "Amount (101) is greater than burst (100)")
+
+	// Closing the bucket fails pending requests
+	tb.SetCap(1)
+	doneCh := tb.Use(100)
+	time.Sleep(10 * time.Millisecond)
+	tb.Close()
+	err = <-doneCh
+	c.HasError(err)
+	c.Contains(err.Error(), "Limiter is closed")
+	err = <-tb.Use(1)
+	c.HasError(err)
+	c.Contains(err.Error(), "Limiter is closed")
+}
+
+func TestTokenBucketHierarchy(t *testing.T) {
+	c := check.New(t)
+	parent := rate.NewTokenBucket(10, 10, time.Hour)
+	child := parent.NewBucket(20, 5)
+	other := parent.New(3)
+	c.Equal(20, child.Cap(false))
+	c.Equal(10, child.Cap(true))
+	c.Equal(5, child.Burst())
+
+	c.True(child.TryUse(5))
+	c.False(child.TryUse(1))
+	c.True(parent.TryUse(3))
+	// The parent now only has 2 tokens left
+	r := child.Reserve(3)
+	c.True(r.OK())
+	r.Cancel()
+	c.Contains(child.Reserve(6).Err().Error(), "greater than burst (5)")
+	c.Equal(0, child.LastUsed()) // Nothing is reported until a full period has elapsed
+
+	otherBucket, ok := other.(rate.TokenBucket)
+	c.True(ok)
+	c.Equal(3, otherBucket.Burst())
+	c.True(otherBucket.TryUse(2))
+	c.False(parent.TryUse(1))
+
+	parent.Close()
+	c.True(child.Closed())
+	c.True(other.Closed())
+	c.Equal(parent, parent.NewBucket(1, 1))
+}
+
+func TestTokenBucketLastUsed(t *testing.T) {
+	c := check.New(t)
+	tb := rate.NewTokenBucket(100, 0, 50*time.Millisecond)
+	defer tb.Close()
+	c.True(tb.TryUse(30))
+	c.Eventually(func() bool { return tb.LastUsed() == 30 }, time.Second, time.Millisecond)
+}
+
+func TestTokenBucketWithManualClock(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	tb := rate.NewTokenBucketWithClock(60, 10, time.Minute, clock)
+	defer tb.Close()
+
+	c.True(tb.TryUse(10))
+	c.Equal(0, tb.Available())
+	clock.Advance(5 * time.Second)
+	c.Equal(5, tb.Available())
+
+	r := tb.Reserve(8)
+	c.True(r.OK())
+	c.Equal(3*time.Second, r.Delay())
+
+	done := make(chan error, 1)
+	go func() { done <- tb.Wait(context.Background(), 1) }()
+	clock.WaitForWaiters(1)
+	clock.Advance(3 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("wait should not have completed yet")
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	err, ok := check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+	c.Equal(19, tb.LastUsed())
+	c.True(tb.TryUse(4))
+	clock.Advance(time.Minute)
+	c.Equal(4, tb.LastUsed())
+}
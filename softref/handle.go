@@ -0,0 +1,113 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package softref
+
+import (
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xruntime"
+)
+
+// Handle is an explicitly managed reference to a resource in a pool. Unlike a SoftRef, the reference is dropped as
+// soon as Release() is called, making it clear when a resource will be released. A Handle that is garbage collected
+// without having been released still drops its reference, but only after the garbage collector gets around to it.
+type Handle struct {
+	pool    *Pool
+	entry   *softRef
+	state   *handleState
+	cleanup runtime.Cleanup
+}
+
+// handleState is kept separate from the Handle so that the cleanup can refer to it without keeping the Handle alive.
+type handleState struct {
+	pool     *Pool
+	entry    *softRef
+	pcs      []uintptr
+	released atomic.Bool
+}
+
+// Acquire returns a Handle to the given resource, along with a flag indicating if a reference existed previously. If a
+// resource with the same key is already in the pool, including one being retained after its last reference went away,
+// the Handle refers to that resource rather than the one passed in.
+func (p *Pool) Acquire(resource Resource) (h *Handle, existedPreviously bool) {
+	key := resource.Key()
+	p.lock.Lock()
+	r, existed := p.acquire(key, resource)
+	p.lock.Unlock()
+	return p.newHandle(r), existed
+}
+
+// Get returns a new Handle to the resource with the key and true if the resource is still live, i.e. it is referenced
+// by a SoftRef or Handle or is being retained. Otherwise, returns nil and false.
+func (p *Pool) Get(key string) (*Handle, bool) {
+	p.lock.Lock()
+	r := p.refs[key]
+	if r == nil {
+		p.misses++
+		p.lock.Unlock()
+		return nil, false
+	}
+	p.hits++
+	p.revive(r)
+	p.lock.Unlock()
+	return p.newHandle(r), true
+}
+
+func (p *Pool) newHandle(r *softRef) *Handle {
+	state := &handleState{pool: p, entry: r}
+	if p.config.LeakReporter != nil {
+		var pcs [64]uintptr
+		state.pcs = pcs[:runtime.Callers(3, pcs[:])]
+	}
+	h := &Handle{pool: p, entry: r, state: state}
+	h.cleanup = runtime.AddCleanup(h, collectHandle, state)
+	return h
+}
+
+func collectHandle(state *handleState) {
+	if state.released.CompareAndSwap(false, true) {
+		if state.pool.config.LeakReporter != nil {
+			state.pool.config.LeakReporter(state.entry.key, xruntime.PCsToStackTrace(state.pcs))
+		}
+		state.pool.release(state.entry)
+	}
+}
+
+// Key returns the key of the resource.
+func (h *Handle) Key() string {
+	return h.entry.key
+}
+
+// Resource returns the resource. It must not be used after the Handle has been released.
+func (h *Handle) Resource() Resource {
+	return h.entry.resource
+}
+
+// Released returns true if Release() has been called.
+func (h *Handle) Released() bool {
+	return h.state.released.Load()
+}
+
+// Release drops the reference to the resource. If this was the last reference, the resource is either retained or
+// released, depending on the pool's configuration. Calling Release() more than once has no further effect.
+func (h *Handle) Release() {
+	if h.state.released.CompareAndSwap(false, true) {
+		h.cleanup.Stop()
+		h.pool.release(h.entry)
+	}
+}
+
+// LogLeak is a function suitable for use as PoolConfig.LeakReporter that logs the leak as a warning.
+func LogLeak(key string, stack []string) {
+	slog.Warn("softref: handle was garbage collected without being released", "key", key, errs.StackTraceKey, stack)
+}
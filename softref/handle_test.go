@@ -0,0 +1,142 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package softref_test
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/softref"
+)
+
+func TestHandleAcquireRelease(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPool()
+	ch := make(chan string, 128)
+	r := newRes("a", ch)
+
+	h1, existed := p.Acquire(r)
+	c.False(existed)
+	c.Equal("a", h1.Key())
+	c.True(h1.Resource() == r)
+	h2, existed := p.Acquire(newRes("a", ch))
+	c.True(existed)
+	c.True(h2.Resource() == r)
+
+	h1.Release()
+	c.True(h1.Released())
+	c.False(h2.Released())
+	h1.Release()
+	select {
+	case k := <-ch:
+		c.Errorf("received key '%s' while a reference remains", k)
+	default:
+	}
+
+	// The last release happens right away, without waiting for the garbage collector
+	h2.Release()
+	k, ok := check.Receive(c, ch, time.Second)
+	c.True(ok)
+	c.Equal("a", k)
+	h2.Release()
+	lookForExpectingTimeout(c, ch)
+}
+
+func TestHandleGet(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPool()
+	ch := make(chan string, 128)
+	r := newRes("b", ch)
+
+	h, ok := p.Get("b")
+	c.False(ok)
+	c.Nil(h)
+
+	h1, _ := p.Acquire(r)
+	h2, ok := p.Get("b")
+	c.True(ok)
+	c.True(h2.Resource() == r)
+	h1.Release()
+	h2.Release()
+	lookFor(c, "b", ch)
+	_, ok = p.Get("b")
+	c.False(ok)
+
+	// Soft references keep the resource live, too
+	sr, _ := p.NewSoftRef(r)
+	h, ok = p.Get("b")
+	c.True(ok)
+	c.True(h.Resource() == sr.Resource)
+	h.Release()
+	runtime.KeepAlive(sr)
+
+	stats := p.Stats()
+	c.Equal(uint64(2), stats.Hits)
+	c.Equal(uint64(4), stats.Misses)
+}
+
+func TestHandleGetRetained(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPoolWithConfig(&softref.PoolConfig{MaxCount: 1})
+	ch := make(chan string, 128)
+	r := newRes("c", ch)
+
+	h, _ := p.Acquire(r)
+	h.Release()
+	c.Equal(1, p.Stats().Retained)
+	h, ok := p.Get("c")
+	c.True(ok)
+	c.True(h.Resource() == r)
+	c.Equal(0, p.Stats().Retained)
+	h.Release()
+	p.Purge()
+	k, ok := check.Receive(c, ch, time.Second)
+	c.True(ok)
+	c.Equal("c", k)
+}
+
+func TestHandleLeakDetection(t *testing.T) {
+	c := check.New(t)
+	var lock sync.Mutex
+	var leakedKey string
+	var leakedStack []string
+	p := softref.NewPoolWithConfig(&softref.PoolConfig{
+		LeakReporter: func(key string, stack []string) {
+			lock.Lock()
+			leakedKey = key
+			leakedStack = stack
+			lock.Unlock()
+		},
+	})
+	ch := make(chan string, 128)
+
+	// Handles that are released are not reported
+	h, _ := p.Acquire(newRes("released", ch))
+	h.Release()
+	lookFor(c, "released", ch)
+
+	leakHandle(p, newRes("leaked", ch))
+	lookFor(c, "leaked", ch)
+	lock.Lock()
+	defer lock.Unlock()
+	c.Equal("leaked", leakedKey)
+	c.NotEqual(0, len(leakedStack))
+	c.True(strings.Contains(leakedStack[0], "leakHandle"), leakedStack[0])
+}
+
+// leakHandle acquires a handle to the resource and then drops it without releasing it.
+func leakHandle(p *softref.Pool, r softref.Resource) {
+	h, _ := p.Acquire(r)
+	runtime.KeepAlive(h)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package softref
+
+import (
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// loadCall tracks a loader that is in progress for a key.
+type loadCall struct {
+	done    chan struct{}
+	entry   *softRef
+	err     error
+	waiters int
+}
+
+// GetOrLoad returns a SoftRef to the resource with the key, calling loader to create the resource if it is not already
+// in the pool. Concurrent calls for the same key share a single call to loader, and all of them receive the same error
+// should it fail. A loader that panics is treated as having returned an error. The resource returned by loader must
+// have the given key. If a resource with the key was added to the pool by other means while loader was running, that
+// resource is used instead and the loaded one is released.
+func (p *Pool) GetOrLoad(key string, loader func() (Resource, error)) (*SoftRef, error) {
+	p.lock.Lock()
+	if r := p.refs[key]; r != nil {
+		p.hits++
+		p.revive(r)
+		p.lock.Unlock()
+		return p.newSoftRef(r), nil
+	}
+	if call, ok := p.loading[key]; ok {
+		call.waiters++
+		p.lock.Unlock()
+		<-call.done
+		if call.err != nil {
+			return nil, call.err
+		}
+		return p.newSoftRef(call.entry), nil
+	}
+	call := &loadCall{done: make(chan struct{})}
+	p.loading[key] = call
+	p.misses++
+	p.lock.Unlock()
+
+	resource, err := load(key, loader)
+	var discard Resource
+	p.lock.Lock()
+	delete(p.loading, key)
+	if err != nil {
+		call.err = err
+	} else {
+		if call.entry = p.refs[key]; call.entry != nil {
+			p.revive(call.entry)
+			if call.entry.resource != resource {
+				discard = resource
+			}
+		} else {
+			call.entry = p.add(key, resource)
+		}
+		// The waiters' references are taken now, so that the resource can't be released before they get to it.
+		call.entry.count += call.waiters
+		p.hits += uint64(call.waiters)
+	}
+	p.lock.Unlock()
+	close(call.done)
+	if discard != nil {
+		discard.Release()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.newSoftRef(call.entry), nil
+}
+
+func load(key string, loader func() (Resource, error)) (resource Resource, err error) {
+	xos.SafeCall(func() { resource, err = loader() }, func(panicErr error) { err = panicErr })
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if resource == nil {
+		return nil, errs.Newf("loader for key %q returned a nil resource", key)
+	}
+	if actual := resource.Key(); actual != key {
+		resource.Release()
+		return nil, errs.Newf("loader for key %q returned a resource with key %q", key, actual)
+	}
+	return resource, nil
+}
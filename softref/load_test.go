@@ -0,0 +1,150 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package softref_test
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/softref"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPool()
+	ch := make(chan string, 128)
+	var calls atomic.Int32
+	loader := func() (softref.Resource, error) {
+		calls.Add(1)
+		return newRes("a", ch), nil
+	}
+
+	sr1, err := p.GetOrLoad("a", loader)
+	c.NoError(err)
+	c.Equal("a", sr1.Key)
+	sr2, err := p.GetOrLoad("a", loader)
+	c.NoError(err)
+	c.True(sr1.Resource == sr2.Resource)
+	c.Equal(int32(1), calls.Load())
+
+	// Existing resources added by other means are found, too
+	r := newRes("b", ch)
+	sr3, _ := p.NewSoftRef(r)
+	sr4, err := p.GetOrLoad("b", func() (softref.Resource, error) {
+		c.Errorf("loader should not have been called")
+		return nil, nil
+	})
+	c.NoError(err)
+	c.True(sr4.Resource == r)
+	runtime.KeepAlive(sr1)
+	runtime.KeepAlive(sr2)
+	runtime.KeepAlive(sr3)
+	runtime.KeepAlive(sr4)
+}
+
+func TestGetOrLoadSingleflight(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPool()
+	ch := make(chan string, 128)
+	var calls atomic.Int32
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	loader := func() (softref.Resource, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-proceed
+		return newRes("shared", ch), nil
+	}
+
+	const count = 10
+	refs := make([]*softref.SoftRef, count)
+	errList := make([]error, count)
+	var wg sync.WaitGroup
+	wg.Go(func() { refs[0], errList[0] = p.GetOrLoad("shared", loader) })
+	<-started
+	for i := 1; i < count; i++ {
+		wg.Go(func() { refs[i], errList[i] = p.GetOrLoad("shared", loader) })
+	}
+	time.Sleep(20 * time.Millisecond) // Give the other callers a chance to start waiting
+	close(proceed)
+	wg.Wait()
+
+	c.Equal(int32(1), calls.Load())
+	for i := range count {
+		c.NoError(errList[i])
+		c.True(refs[i].Resource == refs[0].Resource)
+	}
+	stats := p.Stats()
+	c.Equal(uint64(1), stats.Misses)
+	c.Equal(uint64(count-1), stats.Hits)
+
+	// The resource is only released once every waiter's reference is gone
+	refs = nil
+	lookFor(c, "shared", ch)
+	lookForExpectingTimeout(c, ch)
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPool()
+	ch := make(chan string, 128)
+	loadErr := errors.New("boom")
+	proceed := make(chan struct{})
+	started := make(chan struct{})
+	var calls atomic.Int32
+	loader := func() (softref.Resource, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-proceed
+		return nil, loadErr
+	}
+
+	var wg sync.WaitGroup
+	errList := make([]error, 3)
+	wg.Go(func() { _, errList[0] = p.GetOrLoad("bad", loader) })
+	<-started
+	for i := 1; i < len(errList); i++ {
+		wg.Go(func() { _, errList[i] = p.GetOrLoad("bad", loader) })
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(proceed)
+	wg.Wait()
+	for _, err := range errList {
+		c.True(errors.Is(err, loadErr))
+	}
+
+	// Failures aren't remembered, so the next call tries again
+	proceed = make(chan struct{})
+	close(proceed)
+	_, err := p.GetOrLoad("bad", loader)
+	c.True(errors.Is(err, loadErr))
+	c.True(calls.Load() >= 2)
+
+	// Panics, nil resources and mismatched keys are all reported as errors
+	_, err = p.GetOrLoad("panic", func() (softref.Resource, error) { panic("oops") })
+	c.HasError(err)
+	c.Contains(err.Error(), "recovered from panic")
+	_, err = p.GetOrLoad("nil", func() (softref.Resource, error) { return nil, nil })
+	c.HasError(err)
+	c.Contains(err.Error(), "nil resource")
+	_, err = p.GetOrLoad("wanted", func() (softref.Resource, error) { return newRes("other", ch), nil })
+	c.HasError(err)
+	c.Contains(err.Error(), `returned a resource with key "other"`)
+	k, ok := check.Receive(c, ch, time.Second)
+	c.True(ok)
+	c.Equal("other", k)
+}
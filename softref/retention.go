@@ -0,0 +1,80 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package softref
+
+// Coster may be implemented by a Resource to report its cost, such as the number of bytes of memory it consumes, for
+// the purpose of limiting the total cost of retained resources via PoolConfig.MaxCost. Cost() is called once, when the
+// resource is first added to the pool, and its result must not change.
+type Coster interface {
+	Cost() int64
+}
+
+// Stats holds statistics about a Pool.
+type Stats struct {
+	// Hits is the number of times a request for a soft reference was satisfied by a resource already in the pool.
+	Hits uint64
+	// Misses is the number of times a request for a soft reference required a new resource to be added to the pool.
+	Misses uint64
+	// Retained is the number of resources with no remaining references currently being retained.
+	Retained int
+	// RetainedCost is the total cost of the resources with no remaining references currently being retained.
+	RetainedCost int64
+}
+
+func (c *PoolConfig) retaining() bool {
+	return c.MaxCount > 0 || c.MaxCost > 0
+}
+
+// Stats returns the current statistics for the pool.
+func (p *Pool) Stats() Stats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return Stats{
+		Hits:         p.hits,
+		Misses:       p.misses,
+		Retained:     p.lru.Len(),
+		RetainedCost: p.retainedCost,
+	}
+}
+
+// ResetStats resets the hit and miss counts to zero.
+func (p *Pool) ResetStats() {
+	p.lock.Lock()
+	p.hits = 0
+	p.misses = 0
+	p.lock.Unlock()
+}
+
+// Purge releases all resources that are being retained without any remaining references. Resources that are still
+// referenced are not affected.
+func (p *Pool) Purge() {
+	p.lock.Lock()
+	toRelease := p.evict(-1, -1)
+	p.lock.Unlock()
+	releaseAll(toRelease)
+}
+
+// evict removes retained resources, least recently used first, until no more than maxCount remain and their total cost
+// is no more than maxCost. A limit of zero means no limit, while a negative limit evicts everything. Returns the
+// evicted resources, which the caller must release once the lock is no longer held. Must be called with the lock held.
+func (p *Pool) evict(maxCount int, maxCost int64) []Resource {
+	var evicted []Resource
+	for elem := p.lru.Back(); elem != nil; elem = p.lru.Back() {
+		if (maxCount == 0 || p.lru.Len() <= maxCount) && (maxCost == 0 || p.retainedCost <= maxCost) {
+			break
+		}
+		r := p.lru.Remove(elem).(*softRef) //nolint:errcheck // Only *softRef values are stored
+		r.elem = nil
+		p.retainedCost -= r.cost
+		p.forget(r)
+		evicted = append(evicted, r.resource)
+	}
+	return evicted
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package softref_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/softref"
+)
+
+type costRes struct {
+	res
+	cost int64
+}
+
+func (r *costRes) Cost() int64 {
+	return r.cost
+}
+
+func TestRetentionByCount(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPoolWithConfig(&softref.PoolConfig{MaxCount: 2})
+	ch := make(chan string, 128)
+	r1 := newRes("1", ch)
+
+	dropRef(c, p, r1)
+	waitForRetained(c, p, 1)
+	dropRef(c, p, newRes("2", ch))
+	waitForRetained(c, p, 2)
+	lookForExpectingTimeout(c, ch)
+
+	// A third retained resource pushes out the least recently used one
+	dropRef(c, p, newRes("3", ch))
+	lookFor(c, "1", ch)
+	waitForRetained(c, p, 2)
+
+	// Asking for a retained resource brings it back to life rather than using the new one
+	sr, existed := p.NewSoftRef(newRes("2", ch))
+	c.True(existed)
+	stats := p.Stats()
+	c.Equal(uint64(1), stats.Hits)
+	c.Equal(uint64(3), stats.Misses)
+	c.Equal(1, stats.Retained)
+
+	// Purging only affects unreferenced resources
+	p.Purge()
+	lookFor(c, "3", ch)
+	c.Equal(0, p.Stats().Retained)
+	c.Equal("2", sr.Key)
+	runtime.KeepAlive(sr)
+
+	// The first resource was released, so asking for it again is a miss
+	sr, existed = p.NewSoftRef(r1)
+	c.False(existed)
+	c.True(sr.Resource == r1)
+	c.Equal(uint64(4), p.Stats().Misses)
+
+	p.ResetStats()
+	stats = p.Stats()
+	c.Equal(uint64(0), stats.Hits)
+	c.Equal(uint64(0), stats.Misses)
+}
+
+func TestRetentionByCost(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPoolWithConfig(&softref.PoolConfig{MaxCost: 100})
+	ch := make(chan string, 128)
+
+	dropRef(c, p, &costRes{res: res{key: "a", released: ch}, cost: 60})
+	waitForRetained(c, p, 1)
+	c.Equal(int64(60), p.Stats().RetainedCost)
+	dropRef(c, p, &costRes{res: res{key: "b", released: ch}, cost: 30})
+	waitForRetained(c, p, 2)
+	c.Equal(int64(90), p.Stats().RetainedCost)
+
+	dropRef(c, p, &costRes{res: res{key: "c", released: ch}, cost: 40})
+	lookFor(c, "a", ch)
+	waitForRetained(c, p, 2)
+	c.Equal(int64(70), p.Stats().RetainedCost)
+
+	// A resource that costs more than the limit on its own is released immediately, leaving the others alone
+	dropRef(c, p, &costRes{res: res{key: "d", released: ch}, cost: 101})
+	lookFor(c, "d", ch)
+	c.Equal(int64(70), p.Stats().RetainedCost)
+
+	p.Purge()
+	released := map[string]bool{}
+	for range 2 {
+		k, ok := check.Receive(c, ch, time.Second)
+		c.True(ok)
+		released[k] = true
+	}
+	c.Equal(map[string]bool{"b": true, "c": true}, released)
+	stats := p.Stats()
+	c.Equal(0, stats.Retained)
+	c.Equal(int64(0), stats.RetainedCost)
+}
+
+func TestNoRetentionByDefault(t *testing.T) {
+	c := check.New(t)
+	p := softref.NewPoolWithConfig(nil)
+	ch := make(chan string, 128)
+	dropRef(c, p, newRes("x", ch))
+	lookFor(c, "x", ch)
+	c.Equal(0, p.Stats().Retained)
+}
+
+// dropRef creates a soft reference to the resource and immediately discards it.
+func dropRef(c check.Checker, p *softref.Pool, r softref.Resource) {
+	c.Helper()
+	func() {
+		sr, _ := p.NewSoftRef(r)
+		runtime.KeepAlive(sr)
+	}()
+}
+
+func waitForRetained(c check.Checker, p *softref.Pool, count int) {
+	c.Helper()
+	c.Eventually(func() bool {
+		runtime.GC()
+		return p.Stats().Retained == count
+	}, time.Second, 10*time.Millisecond)
+}
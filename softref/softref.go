@@ -10,6 +10,7 @@
 package softref
 
 import (
+	"container/list"
 	"log/slog"
 	"runtime"
 	"sync"
@@ -17,8 +18,14 @@ import (
 
 // Pool is used to track soft references to resources.
 type Pool struct {
-	refs map[string]*softRef
-	lock sync.Mutex
+	refs         map[string]*softRef
+	loading      map[string]*loadCall
+	lru          *list.List
+	config       PoolConfig
+	retainedCost int64
+	hits         uint64
+	misses       uint64
+	lock         sync.Mutex
 }
 
 // Resource is a resource that will be used with a pool.
@@ -29,66 +36,157 @@ type Resource interface {
 	Release()
 }
 
-// SoftRef is a soft reference to a given resource.
+// SoftRef is a soft reference to a given resource. The reference is dropped when the SoftRef is garbage collected. Use
+// a Handle instead if you need to control when that happens.
 type SoftRef struct {
 	Resource Resource
+	entry    *softRef
 	Key      string
 }
 
 type softRef struct {
 	resource Resource
+	elem     *list.Element
+	key      string
+	cost     int64
 	count    int
 }
 
+// PoolConfig provides configuration for a Pool.
+type PoolConfig struct {
+	// MaxCount is the maximum number of resources with no remaining references that will be retained. Retained
+	// resources are not released until they are evicted to make room for others, in least recently used order, or
+	// purged. Zero or less means no limit, unless MaxCost is also zero or less, in which case no resources are retained
+	// at all.
+	MaxCount int
+	// MaxCost is the maximum total cost of the resources with no remaining references that will be retained. The cost
+	// of a resource is obtained from its Cost() method if it implements Coster, and is zero otherwise. Zero or less
+	// means no limit, unless MaxCount is also zero or less, in which case no resources are retained at all. A resource
+	// whose cost alone exceeds MaxCost is released as soon as it is no longer referenced.
+	MaxCost int64
+	// LeakReporter, if not nil, enables leak detection for Handles. The call stack of each acquisition is recorded,
+	// and if a Handle is garbage collected without having been released, LeakReporter is called with the key of its
+	// resource and the recorded stack. The reference the Handle held is then dropped. Use LogLeak for a reporter that
+	// writes to the default logger.
+	LeakReporter func(key string, stack []string)
+}
+
 // DefaultPool is a global default soft reference pool.
 var DefaultPool = NewPool()
 
-// NewPool creates a new soft reference pool.
+// NewPool creates a new soft reference pool. Resources are released as soon as they are no longer referenced.
 func NewPool() *Pool {
-	return &Pool{refs: make(map[string]*softRef)}
+	return NewPoolWithConfig(nil)
+}
+
+// NewPoolWithConfig creates a new soft reference pool with the given configuration. config may be nil, in which case
+// the pool behaves the same as one created by NewPool().
+func NewPoolWithConfig(config *PoolConfig) *Pool {
+	p := &Pool{
+		refs:    make(map[string]*softRef),
+		loading: make(map[string]*loadCall),
+		lru:     list.New(),
+	}
+	if config != nil {
+		p.config = *config
+		p.config.MaxCount = max(p.config.MaxCount, 0)
+		p.config.MaxCost = max(p.config.MaxCost, 0)
+	}
+	return p
 }
 
 // NewSoftRef returns a SoftRef to the given resource, along with a flag indicating if a reference existed previously.
+// If a resource with the same key is already in the pool, including one being retained after its last reference went
+// away, the SoftRef refers to that resource rather than the one passed in.
 func (p *Pool) NewSoftRef(resource Resource) (ref *SoftRef, existedPreviously bool) {
 	key := resource.Key()
 	p.lock.Lock()
-	defer p.lock.Unlock()
-	r := p.refs[key]
-	if r != nil {
-		r.count++
-	} else {
-		r = &softRef{
-			resource: resource,
-			count:    1,
-		}
-		p.refs[key] = r
-	}
+	r, existed := p.acquire(key, resource)
+	p.lock.Unlock()
+	return p.newSoftRef(r), existed
+}
+
+func (p *Pool) newSoftRef(r *softRef) *SoftRef {
 	sr := &SoftRef{
-		Key:      key,
+		Key:      r.key,
 		Resource: r.resource,
+		entry:    r,
+	}
+	runtime.AddCleanup(sr, p.release, r)
+	return sr
+}
+
+// acquire adds a reference to the entry for the key, creating one for the resource if none exists. Must be called with
+// the lock held.
+func (p *Pool) acquire(key string, resource Resource) (r *softRef, existedPreviously bool) {
+	if r = p.refs[key]; r != nil {
+		p.hits++
+		p.revive(r)
+		return r, true
 	}
-	runtime.SetFinalizer(sr, p.finalizeSoftRef)
-	return sr, r.count > 1
+	p.misses++
+	return p.add(key, resource), false
 }
 
-func (p *Pool) finalizeSoftRef(ref *SoftRef) {
+// add creates a new entry for the resource with a single reference. Must be called with the lock held.
+func (p *Pool) add(key string, resource Resource) *softRef {
+	r := &softRef{
+		resource: resource,
+		key:      key,
+		count:    1,
+	}
+	if p.config.retaining() {
+		if coster, ok := resource.(Coster); ok {
+			r.cost = coster.Cost()
+		}
+	}
+	p.refs[key] = r
+	return r
+}
+
+// revive adds a reference to an existing entry, removing it from the retention list if necessary. Must be called with
+// the lock held.
+func (p *Pool) revive(r *softRef) {
+	if r.elem != nil {
+		p.lru.Remove(r.elem)
+		r.elem = nil
+		p.retainedCost -= r.cost
+	}
+	r.count++
+}
+
+// release drops a reference to the entry, either retaining or releasing its resource once no references remain.
+func (p *Pool) release(r *softRef) {
 	p.lock.Lock()
-	var toRelease Resource
-	if r, ok := p.refs[ref.Key]; ok {
-		r.count--
-		if r.count == 0 {
-			delete(p.refs, ref.Key)
-			toRelease = r.resource
-		} else if r.count < 0 {
-			slog.Debug("SoftRef count is invalid", "key", ref.Key, "count", r.count)
+	var toRelease []Resource
+	r.count--
+	if r.count == 0 {
+		if p.config.retaining() && (p.config.MaxCost == 0 || r.cost <= p.config.MaxCost) {
+			r.elem = p.lru.PushFront(r)
+			p.retainedCost += r.cost
+			toRelease = p.evict(p.config.MaxCount, p.config.MaxCost)
+		} else {
+			p.forget(r)
+			toRelease = append(toRelease, r.resource)
 		}
-	} else {
-		slog.Debug("SoftRef finalized for unknown key", "key", ref.Key)
+	} else if r.count < 0 {
+		slog.Debug("SoftRef count is invalid", "key", r.key, "count", r.count)
 	}
 	p.lock.Unlock()
 	// Release outside the lock: Release() may be slow or re-enter the pool (e.g. acquire another SoftRef), either of
 	// which would otherwise stall every other pool user or deadlock on the non-reentrant mutex.
-	if toRelease != nil {
-		toRelease.Release()
+	releaseAll(toRelease)
+}
+
+// forget removes the entry from the pool, unless it has already been replaced. Must be called with the lock held.
+func (p *Pool) forget(r *softRef) {
+	if p.refs[r.key] == r {
+		delete(p.refs, r.key)
+	}
+}
+
+func releaseAll(resources []Resource) {
+	for _, r := range resources {
+		r.Release()
 	}
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package tid
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+)
+
+var (
+	kindLock  sync.RWMutex
+	kindNames = make(map[byte]string)
+)
+
+// RegisterKind associates a human-readable name with a kind byte, which is then used in error messages about TIDs of
+// that kind. Registering the same name for a kind more than once is permitted, but registering a different name for a
+// kind that already has one is an error.
+func RegisterKind(kind byte, name string) error {
+	if strings.IndexByte(KindAlphabet, kind) == -1 {
+		return errs.New("invalid kind")
+	}
+	if name == "" {
+		return errs.New("kind name may not be empty")
+	}
+	kindLock.Lock()
+	defer kindLock.Unlock()
+	if existing, ok := kindNames[kind]; ok && existing != name {
+		return errs.Newf("kind '%c' is already registered as %q", kind, existing)
+	}
+	kindNames[kind] = name
+	return nil
+}
+
+// KindName returns the name registered for the kind via RegisterKind(), or the kind itself in quotes if no name has
+// been registered.
+func KindName(kind byte) string {
+	kindLock.RLock()
+	name, ok := kindNames[kind]
+	kindLock.RUnlock()
+	if ok {
+		return name
+	}
+	return fmt.Sprintf("'%c'", kind)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package tid_test
+
+import (
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+func TestRegisterKind(t *testing.T) {
+	c := check.New(t)
+
+	// The registry is global, so use a kind that no other test registers
+	c.NoError(tid.RegisterKind('q', "quokkas"))
+	c.NoError(tid.RegisterKind('q', "quokkas"))
+	c.Equal("quokkas", tid.KindName('q'))
+
+	err := tid.RegisterKind('q', "puppies")
+	c.HasError(err)
+	c.Contains(err.Error(), `kind 'q' is already registered as "quokkas"`)
+	c.HasError(tid.RegisterKind('!', "bad"))
+	c.HasError(tid.RegisterKind('j', ""))
+	c.Equal("'j'", tid.KindName('j')) // Never registered
+
+	// Registered names show up in error messages
+	_, err = tid.FromStringOfKind("bad", 'q')
+	c.HasError(err)
+	c.Contains(err.Error(), "invalid TID for kind quokkas")
+	_, err = tid.FromStringOfKind(string(tid.MustNewTID('j')), 'q')
+	c.HasError(err)
+	c.Contains(err.Error(), "TID has kind 'j', but kind quokkas was expected")
+}
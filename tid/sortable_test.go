@@ -0,0 +1,90 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package tid_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/tid"
+)
+
+func TestNewSortableTID(t *testing.T) {
+	c := check.New(t)
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := tid.NewSortableTID('S')
+	c.NoError(err)
+	after := time.Now()
+
+	c.Equal(17, len(id))
+	c.Equal(byte('S'), id[0])
+	c.Equal(byte(tid.SortableMarker), id[1])
+	c.True(tid.IsValid(id))
+	c.True(tid.IsSortable(id))
+	c.False(tid.IsRandom(id))
+	c.True(tid.IsKindAndValid(id, 'S'))
+	c.False(tid.IsKindAndValid(id, 'T'))
+
+	ts, ok := tid.Timestamp(id)
+	c.True(ok)
+	c.False(ts.Before(before), ts, before)
+	c.False(ts.After(after), ts, after)
+
+	fromStr, err := tid.FromStringOfKind(string(id), 'S')
+	c.NoError(err)
+	c.Equal(id, fromStr)
+
+	_, err = tid.NewSortableTID('!')
+	c.HasError(err)
+	c.Panics(func() { tid.MustNewSortableTID('!') })
+}
+
+func TestSortableTIDOrder(t *testing.T) {
+	c := check.New(t)
+	ids := make([]tid.TID, 10000)
+	for i := range ids {
+		ids[i] = tid.MustNewSortableTID('O')
+	}
+	c.True(slices.IsSorted(ids), "sortable TIDs should be in creation order")
+	c.Equal(len(ids), len(slices.Compact(slices.Clone(ids))), "sortable TIDs should be unique")
+
+	first, ok := tid.Timestamp(ids[0])
+	c.True(ok)
+	last, ok := tid.Timestamp(ids[len(ids)-1])
+	c.True(ok)
+	c.False(last.Before(first))
+}
+
+func TestVariantValidation(t *testing.T) {
+	c := check.New(t)
+	random := tid.MustNewTID('R')
+	c.True(tid.IsRandom(random))
+	c.False(tid.IsSortable(random))
+	_, ok := tid.Timestamp(random)
+	c.False(ok)
+
+	for _, id := range []tid.TID{
+		"",
+		"R~",
+		"!~-----------0000",  // invalid kind
+		"R~-----------000!",  // invalid character
+		"R~-----------00000", // too long
+		"R~-----------00=",   // too short
+		"R-~--------------",  // marker in the wrong place
+	} {
+		c.False(tid.IsSortable(id), "Should not be sortable: %q", id)
+	}
+	c.True(tid.IsSortable("R~---------------"))
+	ts, ok := tid.Timestamp("R~---------------")
+	c.True(ok)
+	c.Equal(int64(0), ts.UnixMilli())
+}
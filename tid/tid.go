@@ -20,7 +20,8 @@ import (
 )
 
 // TID is a unique identifier. These are similar to v4 UUIDs, but are shorter and have a different format that includes
-// a kind byte as the first character. TIDs are 17 characters long, are URL safe, and contain 96 bits of entropy.
+// a kind byte as the first character. TIDs are 17 characters long, are URL safe, and contain 96 bits of entropy. See
+// NewSortableTID() for a variant that sorts by creation time.
 type TID string
 
 // KindAlphabet is the set of characters that can be used as the first character of a TID. The kind has no intrinsic
@@ -56,14 +57,22 @@ func FromString(id string) (TID, error) {
 // FromStringOfKind converts a string to a TID and verifies that it has the specified kind.
 func FromStringOfKind(id string, kind byte) (TID, error) {
 	tid := TID(id)
-	if !IsKindAndValid(tid, kind) {
-		return "", errs.New("invalid TID")
+	if !IsValid(tid) {
+		return "", errs.Newf("invalid TID for kind %s", KindName(kind))
+	}
+	if !IsKind(tid, kind) {
+		return "", errs.Newf("TID has kind %s, but kind %s was expected", KindName(tid[0]), KindName(kind))
 	}
 	return tid, nil
 }
 
-// IsValid returns true if the TID is a valid TID.
+// IsValid returns true if the TID is a valid TID, either random or sortable.
 func IsValid(id TID) bool {
+	return IsRandom(id) || IsSortable(id)
+}
+
+// IsRandom returns true if the TID is a valid random TID, i.e. one created by NewTID().
+func IsRandom(id TID) bool {
 	if len(id) != 17 || strings.IndexByte(KindAlphabet, id[0]) == -1 {
 		return false
 	}
@@ -78,9 +87,5 @@ func IsKind(id TID, kind byte) bool {
 
 // IsKindAndValid returns true if the TID is a valid TID with the specified kind.
 func IsKindAndValid(id TID, kind byte) bool {
-	if !IsKind(id, kind) {
-		return false
-	}
-	_, err := base64.RawURLEncoding.DecodeString(string(id[1:]))
-	return err == nil
+	return IsKind(id, kind) && IsValid(id)
 }
@@ -0,0 +1,131 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package tid
+
+import (
+	"database/sql/driver"
+
+	"github.com/richardwilkes/toolbox/v2/errs"
+	"github.com/richardwilkes/toolbox/v2/xos"
+)
+
+// Kind is the constraint for the kind types used with Typed. The method must return a static value and be usable with
+// the zero value of the type.
+//
+// For example:
+//
+//	type UserKind struct{}
+//
+//	func (UserKind) Kind() byte { return 'U' }
+//
+//	type UserID = tid.Typed[UserKind]
+type Kind interface {
+	Kind() byte
+}
+
+// Typed is a TID whose kind is fixed by its type, so that, for example, an id for a user cannot be passed where an id
+// for an order is expected. Values produced by the constructors, TypedFromString(), unmarshaling or scanning are always
+// either empty or a valid TID of its kind. Since Typed is a string type, explicit conversions, such as
+// Typed[K]("text"), bypass this validation and should be avoided. The zero value is empty and is marshaled as an empty
+// string, or as NULL for SQL. Text marshaling is used for both JSON and YAML.
+type Typed[K Kind] TID
+
+// NewTyped creates a new random Typed TID.
+func NewTyped[K Kind]() (Typed[K], error) {
+	var k K
+	id, err := NewTID(k.Kind())
+	return Typed[K](id), err
+}
+
+// MustNewTyped creates a new random Typed TID. If an error occurs, this function panics.
+func MustNewTyped[K Kind]() Typed[K] {
+	return xos.Must(NewTyped[K]())
+}
+
+// NewSortableTyped creates a new sortable Typed TID. See NewSortableTID() for details.
+func NewSortableTyped[K Kind]() (Typed[K], error) {
+	var k K
+	id, err := NewSortableTID(k.Kind())
+	return Typed[K](id), err
+}
+
+// MustNewSortableTyped creates a new sortable Typed TID. If an error occurs, this function panics.
+func MustNewSortableTyped[K Kind]() Typed[K] {
+	return xos.Must(NewSortableTyped[K]())
+}
+
+// TypedFromString converts a string to a Typed TID, verifying that it is a valid TID of the appropriate kind.
+func TypedFromString[K Kind](id string) (Typed[K], error) {
+	var k K
+	t, err := FromStringOfKind(id, k.Kind())
+	return Typed[K](t), err
+}
+
+// TID returns the underlying TID.
+func (t Typed[K]) TID() TID {
+	return TID(t)
+}
+
+// String implements fmt.Stringer.
+func (t Typed[K]) String() string {
+	return string(t)
+}
+
+// IsZero returns true if this is the zero value.
+func (t Typed[K]) IsZero() bool {
+	return t == ""
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t Typed[K]) MarshalText() ([]byte, error) {
+	return []byte(t), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text produces the zero value.
+func (t *Typed[K]) UnmarshalText(text []byte) error {
+	return t.set(string(text))
+}
+
+// Scan implements sql.Scanner. NULL and empty values produce the zero value.
+func (t *Typed[K]) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = ""
+		return nil
+	case string:
+		return t.set(v)
+	case []byte:
+		return t.set(string(v))
+	default:
+		var k K
+		return errs.Newf("unable to scan %T into a TID for kind %s", src, KindName(k.Kind()))
+	}
+}
+
+// Value implements driver.Valuer. The zero value is stored as NULL.
+func (t Typed[K]) Value() (driver.Value, error) {
+	if t == "" {
+		return nil, nil
+	}
+	return string(t), nil
+}
+
+func (t *Typed[K]) set(id string) error {
+	if id == "" {
+		*t = ""
+		return nil
+	}
+	v, err := TypedFromString[K](id)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package tid_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/tid"
+	"gopkg.in/yaml.v3"
+)
+
+type userKind struct{}
+
+func (userKind) Kind() byte { return 'U' }
+
+type orderKind struct{}
+
+func (orderKind) Kind() byte { return 'O' }
+
+type (
+	userID  = tid.Typed[userKind]
+	orderID = tid.Typed[orderKind]
+)
+
+var (
+	_ sql.Scanner   = (*userID)(nil)
+	_ driver.Valuer = userID("")
+)
+
+type record struct {
+	User  userID  `json:"user" yaml:"user"`
+	Order orderID `json:"order,omitempty" yaml:"order,omitempty"`
+}
+
+func TestNewTyped(t *testing.T) {
+	c := check.New(t)
+	id, err := tid.NewTyped[userKind]()
+	c.NoError(err)
+	c.True(tid.IsKindAndValid(id.TID(), 'U'))
+	c.True(tid.IsRandom(id.TID()))
+	c.Equal(string(id), id.String())
+	c.False(id.IsZero())
+	c.True(userID("").IsZero())
+
+	sortable := tid.MustNewSortableTyped[orderKind]()
+	c.True(tid.IsSortable(sortable.TID()))
+	c.True(tid.IsKind(sortable.TID(), 'O'))
+	c.True(tid.IsKind(tid.MustNewTyped[orderKind]().TID(), 'O'))
+	_, err = tid.NewSortableTyped[userKind]()
+	c.NoError(err)
+}
+
+func TestTypedFromString(t *testing.T) {
+	c := check.New(t)
+	u := tid.MustNewTyped[userKind]()
+	got, err := tid.TypedFromString[userKind](u.String())
+	c.NoError(err)
+	c.Equal(u, got)
+
+	_, err = tid.TypedFromString[orderKind](u.String())
+	c.HasError(err)
+	c.Contains(err.Error(), "but kind 'O' was expected")
+	_, err = tid.TypedFromString[userKind]("nope")
+	c.HasError(err)
+}
+
+func TestTypedText(t *testing.T) {
+	c := check.New(t)
+	u := tid.MustNewTyped[userKind]()
+	text, err := u.MarshalText()
+	c.NoError(err)
+	var u2 userID
+	c.NoError(u2.UnmarshalText(text))
+	c.Equal(u, u2)
+	c.NoError(u2.UnmarshalText(nil))
+	c.True(u2.IsZero())
+	c.HasError(u2.UnmarshalText([]byte(tid.MustNewTID('O'))))
+}
+
+func TestTypedJSON(t *testing.T) {
+	c := check.New(t)
+	in := record{User: tid.MustNewTyped[userKind](), Order: tid.MustNewSortableTyped[orderKind]()}
+	data, err := json.Marshal(in)
+	c.NoError(err)
+	c.Equal(`{"user":"`+in.User.String()+`","order":"`+in.Order.String()+`"}`, string(data))
+	var out record
+	c.NoError(json.Unmarshal(data, &out))
+	c.Equal(in, out)
+
+	// The zero value round-trips as an empty string and null leaves the value alone
+	data, err = json.Marshal(record{})
+	c.NoError(err)
+	c.Equal(`{"user":""}`, string(data))
+	out = record{User: in.User}
+	c.NoError(json.Unmarshal([]byte(`{"user":null,"order":""}`), &out))
+	c.Equal(record{User: in.User}, out)
+
+	// Ids of the wrong kind are rejected
+	err = json.Unmarshal([]byte(`{"user":"`+in.Order.String()+`"}`), &out)
+	c.HasError(err)
+	c.Contains(err.Error(), "but kind 'U' was expected")
+	c.HasError(json.Unmarshal([]byte(`{"user":12}`), &out))
+
+	// Even unvalidated values produce valid JSON
+	data, err = json.Marshal(record{User: userID("bad\x01\"é<")})
+	c.NoError(err)
+	c.True(json.Valid(data), string(data))
+}
+
+func TestTypedYAML(t *testing.T) {
+	c := check.New(t)
+	in := record{User: tid.MustNewTyped[userKind](), Order: tid.MustNewTyped[orderKind]()}
+	data, err := yaml.Marshal(in)
+	c.NoError(err)
+	c.Equal("user: "+in.User.String()+"\norder: "+in.Order.String()+"\n", string(data))
+	var out record
+	c.NoError(yaml.Unmarshal(data, &out))
+	c.Equal(in, out)
+
+	err = yaml.Unmarshal([]byte("user: "+in.Order.String()+"\n"), &out)
+	c.HasError(err)
+	c.Contains(err.Error(), "but kind 'U' was expected")
+}
+
+func TestTypedSQL(t *testing.T) {
+	c := check.New(t)
+	u := tid.MustNewTyped[userKind]()
+	v, err := u.Value()
+	c.NoError(err)
+	c.Equal(u.String(), v)
+	v, err = userID("").Value()
+	c.NoError(err)
+	c.Nil(v)
+
+	var scanned userID
+	c.NoError(scanned.Scan(u.String()))
+	c.Equal(u, scanned)
+	c.NoError(scanned.Scan(nil))
+	c.True(scanned.IsZero())
+	c.NoError(scanned.Scan([]byte(u.String())))
+	c.Equal(u, scanned)
+	c.HasError(scanned.Scan(string(tid.MustNewTID('O'))))
+	err = scanned.Scan(42)
+	c.HasError(err)
+	c.Contains(err.Error(), "unable to scan int into a TID for kind 'U'")
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package xtime
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time along with timers and tickers, allowing code that depends on the passage of time to
+// be tested deterministically by substituting a ManualClock for RealClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a new Timer that will send the current time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a new Ticker that will send the current time on its channel every period d. d must be greater
+	// than zero.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is the equivalent of a time.Timer for a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. Returns true if the call stops the timer, false if the timer has already
+	// expired or been stopped.
+	Stop() bool
+	// Reset changes the timer to expire after duration d. Returns true if the timer had been active, false if the timer
+	// had expired or been stopped.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the equivalent of a time.Ticker for a Clock.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker. After Stop, no more ticks will be sent.
+	Stop()
+	// Reset stops the ticker and resets its period to d. The next tick will arrive after the new period elapses. d must
+	// be greater than zero.
+	Reset(d time.Duration)
+}
+
+// RealClock is a Clock that uses the system time.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+type realTimer struct {
+	*time.Timer
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{Timer: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{Ticker: time.NewTicker(d)}
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// ManualClock is a Clock whose time only changes when explicitly told to, making it suitable for tests. Timers and
+// tickers created from it fire as the time is advanced past their deadlines. As with their counterparts in the time
+// package as of Go 1.23, at most one value is pending on a channel at a time, so a tick is dropped if the previous one
+// has not yet been received, and calling Stop() or Reset() discards any pending value, so no stale value will be
+// received afterwards.
+type ManualClock struct {
+	now    time.Time
+	cond   *sync.Cond
+	timers []*manualTimer
+	lock   sync.Mutex
+}
+
+type manualTimer struct {
+	when   time.Time
+	clock  *ManualClock
+	ch     chan time.Time
+	period time.Duration
+}
+
+type manualTicker struct {
+	*manualTimer
+}
+
+// NewManualClock creates a new ManualClock whose current time is start.
+func NewManualClock(start time.Time) *ManualClock {
+	c := &ManualClock{now: start}
+	c.cond = sync.NewCond(&c.lock)
+	return c
+}
+
+// Now returns the current time.
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// NewTimer creates a new Timer that will send the current time on its channel once the clock has been advanced by at
+// least duration d. A duration of zero or less fires immediately.
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	t := &manualTimer{clock: c, ch: make(chan time.Time, 1)}
+	t.reset(d, 0)
+	return t
+}
+
+// NewTicker creates a new Ticker that will send the current time on its channel each time the clock advances through
+// another period d. Panics if d is not greater than zero.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	t := manualTicker{manualTimer: &manualTimer{clock: c, ch: make(chan time.Time, 1)}}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by d, firing any timers and tickers whose deadlines are reached along the way, in
+// deadline order.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.advanceTo(c.now.Add(d))
+}
+
+// Set moves the clock to t, firing any timers and tickers whose deadlines are reached along the way, in deadline order.
+// If t is before the current time, the time is changed but no timers fire.
+func (c *ManualClock) Set(t time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.advanceTo(t)
+}
+
+// Waiters returns the number of timers and tickers that are currently active.
+func (c *ManualClock) Waiters() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.timers)
+}
+
+// WaitForWaiters blocks until at least n timers and tickers are active. This is useful for ensuring that code running
+// on another goroutine has started waiting before advancing the clock.
+func (c *ManualClock) WaitForWaiters(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *ManualClock) advanceTo(target time.Time) {
+	for {
+		var next *manualTimer
+		for _, t := range c.timers {
+			if !t.when.After(target) && (next == nil || t.when.Before(next.when)) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		if next.when.After(c.now) {
+			c.now = next.when
+		}
+		select {
+		case next.ch <- c.now:
+		default:
+		}
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+	}
+	c.now = target
+}
+
+func (c *ManualClock) remove(t *manualTimer) bool {
+	if i := slices.Index(c.timers, t); i != -1 {
+		c.timers = slices.Delete(c.timers, i, i+1)
+		return true
+	}
+	return false
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	active := t.clock.remove(t)
+	return t.drain() || active
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	return t.reset(d, 0)
+}
+
+func (t *manualTimer) reset(d, period time.Duration) bool {
+	c := t.clock
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	active := c.remove(t)
+	active = t.drain() || active
+	t.period = period
+	t.when = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	c.advanceTo(c.now)
+	return active
+}
+
+// drain discards any value pending on the channel, returning true if there was one. A timer with a pending value is
+// treated as still active, as it is in the time package. Must be called with the clock lock held.
+func (t *manualTimer) drain() bool {
+	select {
+	case <-t.ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t manualTicker) Stop() {
+	t.manualTimer.Stop()
+}
+
+func (t manualTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.reset(d, d)
+}
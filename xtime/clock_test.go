@@ -0,0 +1,181 @@
+// Copyright (c) 2016-2026 by Richard A. Wilkes. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, version 2.0. If a copy of the MPL was not distributed with
+// this file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// This Source Code Form is "Incompatible With Secondary Licenses", as
+// defined by the Mozilla Public License, version 2.0.
+
+package xtime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/richardwilkes/toolbox/v2/check"
+	"github.com/richardwilkes/toolbox/v2/xtime"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	c := check.New(t)
+	before := time.Now()
+	c.False(xtime.RealClock.Now().Before(before))
+
+	timer := xtime.RealClock.NewTimer(time.Millisecond)
+	_, ok := check.Receive(c, timer.C(), time.Second)
+	c.True(ok)
+	c.False(timer.Stop())
+	c.False(timer.Reset(time.Hour))
+	c.True(timer.Stop())
+
+	ticker := xtime.RealClock.NewTicker(time.Millisecond)
+	_, ok = check.Receive(c, ticker.C(), time.Second)
+	c.True(ok)
+	ticker.Reset(time.Millisecond)
+	_, ok = check.Receive(c, ticker.C(), time.Second)
+	c.True(ok)
+	ticker.Stop()
+}
+
+func TestManualClockTimer(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(epoch)
+	c.Equal(epoch, clock.Now())
+
+	timer := clock.NewTimer(time.Minute)
+	c.Equal(1, clock.Waiters())
+	clock.Advance(59 * time.Second)
+	_, ok := fired(timer.C())
+	c.False(ok)
+	clock.Advance(2 * time.Second)
+	when, ok := fired(timer.C())
+	c.True(ok)
+	c.Equal(epoch.Add(time.Minute), when)
+	c.Equal(epoch.Add(61*time.Second), clock.Now())
+	c.Equal(0, clock.Waiters())
+	c.False(timer.Stop())
+
+	c.False(timer.Reset(time.Second))
+	c.True(timer.Reset(time.Second))
+	c.True(timer.Stop())
+	clock.Advance(time.Hour)
+	_, ok = fired(timer.C())
+	c.False(ok)
+
+	immediate := clock.NewTimer(0)
+	_, ok = fired(immediate.C())
+	c.True(ok)
+}
+
+func TestManualClockTicker(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(epoch)
+	ticker := clock.NewTicker(10 * time.Second)
+	early := clock.NewTimer(15 * time.Second)
+
+	clock.Advance(10 * time.Second)
+	when, ok := fired(ticker.C())
+	c.True(ok)
+	c.Equal(epoch.Add(10*time.Second), when)
+
+	// Deadlines are reached in order, and a tick is dropped if the previous one was not received
+	clock.Set(epoch.Add(35 * time.Second))
+	when, ok = fired(early.C())
+	c.True(ok)
+	c.Equal(epoch.Add(15*time.Second), when)
+	when, ok = fired(ticker.C())
+	c.True(ok)
+	c.Equal(epoch.Add(20*time.Second), when)
+	_, ok = fired(ticker.C())
+	c.False(ok)
+
+	ticker.Reset(time.Second)
+	clock.Advance(time.Second)
+	when, ok = fired(ticker.C())
+	c.True(ok)
+	c.Equal(epoch.Add(36*time.Second), when)
+
+	ticker.Stop()
+	clock.Advance(time.Minute)
+	_, ok = fired(ticker.C())
+	c.False(ok)
+	c.Panics(func() { clock.NewTicker(0) })
+}
+
+func TestManualClockStopAfterFire(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(epoch)
+	timer := clock.NewTimer(time.Second)
+	clock.Advance(time.Second)
+
+	// As with the time package, a timer whose value has not been received is still considered active, and stopping it
+	// discards the value
+	c.True(timer.Stop())
+	_, ok := fired(timer.C())
+	c.False(ok)
+	c.False(timer.Stop())
+
+	ticker := clock.NewTicker(time.Second)
+	clock.Advance(time.Second)
+	ticker.Stop()
+	_, ok = fired(ticker.C())
+	c.False(ok)
+}
+
+func TestManualClockResetAfterFire(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(epoch)
+	timer := clock.NewTimer(time.Second)
+	clock.Advance(time.Second)
+
+	c.True(timer.Reset(time.Minute))
+	_, ok := fired(timer.C())
+	c.False(ok)
+	clock.Advance(time.Minute)
+	when, ok := fired(timer.C())
+	c.True(ok)
+	c.Equal(epoch.Add(61*time.Second), when)
+
+	ticker := clock.NewTicker(time.Second)
+	clock.Advance(time.Second)
+	ticker.Reset(time.Minute)
+	_, ok = fired(ticker.C())
+	c.False(ok)
+	clock.Advance(time.Minute)
+	when, ok = fired(ticker.C())
+	c.True(ok)
+	c.Equal(epoch.Add(122*time.Second), when)
+}
+
+func TestSleepWithManualClock(t *testing.T) {
+	c := check.New(t)
+	clock := xtime.NewManualClock(epoch)
+	done := make(chan error, 1)
+	go func() { done <- xtime.SleepWithClock(context.Background(), clock, time.Hour) }()
+	clock.WaitForWaiters(1)
+	clock.Advance(time.Hour)
+	err, ok := check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { done <- xtime.SleepWithClock(ctx, clock, time.Hour) }()
+	clock.WaitForWaiters(1)
+	cancel()
+	err, ok = check.Receive(c, done, time.Second)
+	c.True(ok)
+	c.Equal(context.Canceled, err)
+}
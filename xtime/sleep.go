@@ -16,12 +16,17 @@ import (
 
 // Sleep for the specified Duration or until the context is done.
 func Sleep(ctx context.Context, d time.Duration) error {
-	timer := time.NewTimer(d)
+	return SleepWithClock(ctx, RealClock, d)
+}
+
+// SleepWithClock sleeps for the specified Duration, as measured by the clock, or until the context is done.
+func SleepWithClock(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
 	defer timer.Stop()
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
-	case <-timer.C:
+	case <-timer.C():
 		return nil
 	}
 }